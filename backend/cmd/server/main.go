@@ -1,13 +1,18 @@
 package main
 
 import (
+	"context"
 	"log"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"erp-backend/internal/config"
 	"erp-backend/internal/db"
+	"erp-backend/internal/handlers"
+	"erp-backend/internal/jobs"
 	"erp-backend/internal/routes"
+	"erp-backend/internal/storage"
 )
 
 func main() {
@@ -21,10 +26,32 @@ func main() {
 		log.Fatalf("db error: %v", err)
 	}
 
+	store, err := storage.New(storage.Config{
+		Backend:     cfg.StorageBackend,
+		LocalDir:    cfg.StorageDir,
+		S3Endpoint:  cfg.S3Endpoint,
+		S3Region:    cfg.S3Region,
+		S3Bucket:    cfg.S3Bucket,
+		S3AccessKey: cfg.S3AccessKey,
+		S3SecretKey: cfg.S3SecretKey,
+	})
+	if err != nil {
+		log.Fatalf("storage error: %v", err)
+	}
+
+	jobs.Start(context.Background(),
+		jobs.Job{Name: "recurring-invoices", Interval: 15 * time.Minute, Run: handlers.NewRecurringInvoiceHandler(database, cfg).RunDue},
+		jobs.Job{Name: "overdue-invoices", Interval: 15 * time.Minute, Run: handlers.NewInvoiceHandler(database).RunOverdue},
+		jobs.Job{Name: "invoice-reminders", Interval: time.Hour, Run: handlers.NewInvoiceReminderHandler(database, cfg).RunDue},
+		jobs.Job{Name: "employee-terminations", Interval: time.Hour, Run: handlers.NewEmployeeHandler(database).RunTerminations},
+		jobs.Job{Name: "compensation-changes", Interval: time.Hour, Run: handlers.NewEmployeeHandler(database).RunCompensationChanges},
+		jobs.Job{Name: "payslip-emails", Interval: time.Minute, Run: handlers.NewPayrollHandler(database, cfg).RunPayslipEmails},
+	)
+
 	router := gin.New()
 	router.Use(gin.Logger(), gin.Recovery())
 
-	routes.Register(router, database, cfg)
+	routes.Register(router, database, cfg, store)
 
 	if err := router.Run(cfg.Addr); err != nil {
 		log.Fatalf("server error: %v", err)
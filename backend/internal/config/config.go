@@ -24,6 +24,13 @@ type Config struct {
 	SmtpPass          string
 	SmtpFrom          string
 	AllowedOriginsRaw string
+	StorageBackend    string
+	StorageDir        string
+	S3Endpoint        string
+	S3Region          string
+	S3Bucket          string
+	S3AccessKey       string
+	S3SecretKey       string
 }
 
 func Load() (Config, error) {
@@ -44,6 +51,13 @@ func Load() (Config, error) {
 		SmtpPass:          os.Getenv("SMTP_PASS"),
 		SmtpFrom:          os.Getenv("SMTP_FROM"),
 		AllowedOriginsRaw: getEnv("ALLOWED_ORIGINS", ""),
+		StorageBackend:    getEnv("STORAGE_BACKEND", "local"),
+		StorageDir:        getEnv("STORAGE_DIR", "storage"),
+		S3Endpoint:        os.Getenv("S3_ENDPOINT"),
+		S3Region:          getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:          os.Getenv("S3_BUCKET"),
+		S3AccessKey:       os.Getenv("S3_ACCESS_KEY"),
+		S3SecretKey:       os.Getenv("S3_SECRET_KEY"),
 	}
 
 	missing := []string{}
@@ -66,6 +80,21 @@ func Load() (Config, error) {
 		missing = append(missing, "SMTP_FROM")
 	}
 
+	if cfg.StorageBackend == "s3" {
+		if cfg.S3Endpoint == "" {
+			missing = append(missing, "S3_ENDPOINT")
+		}
+		if cfg.S3Bucket == "" {
+			missing = append(missing, "S3_BUCKET")
+		}
+		if cfg.S3AccessKey == "" {
+			missing = append(missing, "S3_ACCESS_KEY")
+		}
+		if cfg.S3SecretKey == "" {
+			missing = append(missing, "S3_SECRET_KEY")
+		}
+	}
+
 	if len(missing) > 0 {
 		return cfg, errors.New("missing env: " + strings.Join(missing, ", "))
 	}
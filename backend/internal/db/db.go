@@ -8,7 +8,10 @@ import (
 )
 
 func Open(dsn string) (*gorm.DB, error) {
-	database, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	database, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
+		// Unique key violations come back as gorm.ErrDuplicatedKey.
+		TranslateError: true,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -16,18 +19,54 @@ func Open(dsn string) (*gorm.DB, error) {
 	if err := database.AutoMigrate(
 		&models.User{},
 		&models.Setting{},
+		&models.NumberSequence{},
+		&models.NumberSequenceYear{},
 		&models.OTP{},
 		&models.RefreshToken{},
+		&models.AuditEvent{},
+		&models.Department{},
 		&models.Employee{},
+		&models.EmployeeDocument{},
+		&models.CompensationChange{},
+		&models.Customer{},
 		&models.Invoice{},
+		&models.InvoiceLine{},
+		&models.Payment{},
+		&models.CreditNote{},
+		&models.InvoiceReminder{},
+		&models.RecurringInvoice{},
+		&models.RecurringInvoiceLine{},
+		&models.ExchangeRate{},
 		&models.Attendance{},
 		&models.AttendanceBreak{},
+		&models.AttendanceCorrection{},
+		&models.AttendanceCorrectionBreak{},
+		&models.Shift{},
+		&models.ShiftAssignment{},
+		&models.Timesheet{},
 		&models.LeaveBalance{},
 		&models.LeavePolicy{},
 		&models.LeaveRequest{},
+		&models.PayrollComponent{},
+		&models.PayrollRun{},
+		&models.Payslip{},
+		&models.PayslipLine{},
 	); err != nil {
 		return nil, err
 	}
 
+	if err := backfillInvoiceCustomers(database); err != nil {
+		return nil, err
+	}
+	if err := backfillInvoicePayments(database); err != nil {
+		return nil, err
+	}
+	if err := seedNumberSequences(database); err != nil {
+		return nil, err
+	}
+	if err := backfillCompensationHistory(database); err != nil {
+		return nil, err
+	}
+
 	return database, nil
 }
@@ -0,0 +1,153 @@
+package db
+
+import (
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"erp-backend/internal/models"
+)
+
+// backfillInvoiceCustomers links invoices created before customers existed to
+// a Customer record, creating one per distinct customer_name. Names are
+// matched case-insensitively by the database collation, so spelling variants
+// that differ only in case collapse into the same customer.
+func backfillInvoiceCustomers(database *gorm.DB) error {
+	var names []string
+	if err := database.Model(&models.Invoice{}).
+		Where("customer_id IS NULL").
+		Distinct("customer_name").
+		Pluck("customer_name", &names).Error; err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		trimmed := strings.TrimSpace(name)
+		if trimmed == "" {
+			continue
+		}
+		if err := database.Transaction(func(tx *gorm.DB) error {
+			var customer models.Customer
+			err := tx.Where("name = ?", trimmed).First(&customer).Error
+			if err == gorm.ErrRecordNotFound {
+				customer = models.Customer{Name: trimmed, PaymentTermsDays: 30, Currency: "USD"}
+				err = tx.Create(&customer).Error
+			}
+			if err != nil {
+				return err
+			}
+			return tx.Model(&models.Invoice{}).
+				Where("customer_id IS NULL AND customer_name = ?", name).
+				Updates(map[string]any{
+					"customer_id":   customer.ID,
+					"customer_name": customer.Name,
+				}).Error
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backfillInvoicePayments records a payment for invoices marked paid before
+// payments were tracked, so they count towards amount paid and revenue. The
+// payment is dated at the invoice's last update, the best record left of
+// when it was settled.
+func backfillInvoicePayments(database *gorm.DB) error {
+	var invoices []models.Invoice
+	if err := database.Unscoped().
+		Where("status = ? AND NOT EXISTS (SELECT 1 FROM payments WHERE payments.invoice_id = invoices.id)", "paid").
+		Find(&invoices).Error; err != nil {
+		return err
+	}
+	for _, invoice := range invoices {
+		amount := invoice.AmountPaid
+		if amount <= 0 {
+			amount = invoice.Amount - invoice.Credited
+		}
+		if amount <= 0 {
+			continue
+		}
+		if err := database.Transaction(func(tx *gorm.DB) error {
+			payment := models.Payment{
+				InvoiceID: invoice.ID,
+				Amount:    amount,
+				PaidAt:    invoice.UpdatedAt,
+				Method:    "other",
+				Reference: "Recorded before payment tracking",
+			}
+			if err := tx.Create(&payment).Error; err != nil {
+				return err
+			}
+			return tx.Unscoped().Model(&models.Invoice{}).
+				Where("id = ?", invoice.ID).
+				UpdateColumn("amount_paid", amount).Error
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// seedNumberSequences makes sure every document sequence row exists up front,
+// so allocation only ever has to lock an existing row.
+func seedNumberSequences(database *gorm.DB) error {
+	defaults := []models.NumberSequence{
+		{Key: "invoice", Prefix: "INV", YearlyReset: true, Padding: 5, Year: time.Now().Year(), NextValue: 1},
+		{Key: "credit_note", Prefix: "CN", YearlyReset: true, Padding: 5, Year: time.Now().Year(), NextValue: 1},
+	}
+	for _, sequence := range defaults {
+		if err := database.Where(models.NumberSequence{Key: sequence.Key}).
+			Attrs(sequence).
+			FirstOrCreate(&models.NumberSequence{}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backfillCompensationHistory gives employees created before salary history
+// existed a salary currency and an opening compensation record at their
+// hire date, so the current salary can be derived from the history.
+func backfillCompensationHistory(database *gorm.DB) error {
+	currency := "USD"
+	var values []string
+	if err := database.Model(&models.Setting{}).Where("`key` = ?", "base_currency").Pluck("value", &values).Error; err != nil {
+		return err
+	}
+	if len(values) > 0 && len(strings.TrimSpace(values[0])) == 3 {
+		currency = strings.ToUpper(strings.TrimSpace(values[0]))
+	}
+	if err := database.Unscoped().Model(&models.Employee{}).
+		Where("salary_currency IS NULL OR salary_currency = ''").
+		Update("salary_currency", currency).Error; err != nil {
+		return err
+	}
+
+	var employees []models.Employee
+	if err := database.Unscoped().
+		Where("salary > 0 AND NOT EXISTS (SELECT 1 FROM compensation_changes WHERE compensation_changes.employee_id = employees.id)").
+		Find(&employees).Error; err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, employee := range employees {
+		hiredAt := employee.HiredAt
+		change := models.CompensationChange{
+			EmployeeID:    employee.ID,
+			EffectiveDate: time.Date(hiredAt.Year(), hiredAt.Month(), hiredAt.Day(), 0, 0, 0, 0, time.UTC),
+			Amount:        employee.Salary,
+			Currency:      employee.SalaryCurrency,
+			Reason:        "Opening balance",
+			AppliedAt:     &now,
+		}
+		if change.Currency == "" {
+			change.Currency = currency
+		}
+		if err := database.Create(&change).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
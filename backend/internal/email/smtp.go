@@ -1,8 +1,12 @@
 package email
 
 import (
+	"crypto/rand"
 	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
+	"mime"
 	"net"
 	"net/smtp"
 	"strings"
@@ -16,11 +20,31 @@ type Config struct {
 	From     string
 }
 
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
 func SendOTP(cfg Config, to string, code string) error {
 	subject := "Your WorkFlow ERP OTP Code"
 	body := "Your OTP code is: " + code + "\nThis code expires soon."
-	message := buildMessage(cfg.From, to, subject, body)
+	return send(cfg, to, buildMessage(cfg.From, to, subject, body))
+}
 
+// SendMessage sends a plain-text email with optional attachments.
+func SendMessage(cfg Config, to string, subject string, body string, attachments ...Attachment) error {
+	if len(attachments) == 0 {
+		return send(cfg, to, buildMessage(cfg.From, to, subject, body))
+	}
+	message, err := buildMultipartMessage(cfg.From, to, subject, body, attachments)
+	if err != nil {
+		return err
+	}
+	return send(cfg, to, message)
+}
+
+func send(cfg Config, to string, message string) error {
 	addr := fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)
 	fromAddr := parseAddress(cfg.From)
 	auth := smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
@@ -81,7 +105,7 @@ func buildMessage(from string, to string, subject string, body string) string {
 	headers := []string{
 		"From: " + from,
 		"To: " + to,
-		"Subject: " + subject,
+		"Subject: " + mime.QEncoding.Encode("utf-8", subject),
 		"MIME-Version: 1.0",
 		"Content-Type: text/plain; charset=utf-8",
 		"",
@@ -90,6 +114,54 @@ func buildMessage(from string, to string, subject string, body string) string {
 	return strings.Join(headers, "\r\n")
 }
 
+func buildMultipartMessage(from string, to string, subject string, body string, attachments []Attachment) (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	boundary := "erp-" + hex.EncodeToString(buf)
+
+	parts := []string{
+		"From: " + from,
+		"To: " + to,
+		"Subject: " + mime.QEncoding.Encode("utf-8", subject),
+		"MIME-Version: 1.0",
+		`Content-Type: multipart/mixed; boundary="` + boundary + `"`,
+		"",
+		"--" + boundary,
+		"Content-Type: text/plain; charset=utf-8",
+		"",
+		body,
+	}
+	for _, attachment := range attachments {
+		contentType := attachment.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		filename := strings.ReplaceAll(attachment.Filename, `"`, "")
+		parts = append(parts,
+			"--"+boundary,
+			"Content-Type: "+contentType+`; name="`+filename+`"`,
+			"Content-Transfer-Encoding: base64",
+			`Content-Disposition: attachment; filename="`+filename+`"`,
+			"",
+			wrapBase64(base64.StdEncoding.EncodeToString(attachment.Data)),
+		)
+	}
+	parts = append(parts, "--"+boundary+"--", "")
+	return strings.Join(parts, "\r\n"), nil
+}
+
+func wrapBase64(encoded string) string {
+	lines := []string{}
+	for len(encoded) > 76 {
+		lines = append(lines, encoded[:76])
+		encoded = encoded[76:]
+	}
+	lines = append(lines, encoded)
+	return strings.Join(lines, "\r\n")
+}
+
 func parseAddress(from string) string {
 	start := strings.Index(from, "<")
 	end := strings.Index(from, ">")
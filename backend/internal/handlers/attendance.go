@@ -119,8 +119,35 @@ func (h *AttendanceHandler) findOpenAttendance(c *gin.Context, attendanceID stri
 	return record, nil
 }
 
+var attendanceSortColumns = map[string]string{
+	"createdAt": "created_at",
+	"checkIn":   "check_in",
+	"checkOut":  "check_out",
+}
+
+// List pages attendance records; breaks are preloaded only for the records
+// on the requested page.
 func (h *AttendanceHandler) List(c *gin.Context) {
+	params, message := parseListParams(c, attendanceSortColumns, "createdAt")
+	if message != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return
+	}
+	from, to, message := parseDateRange(c)
+	if message != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return
+	}
+
+	query := h.DB.Model(&models.Attendance{})
 	role, _ := c.Get(middleware.ContextRole)
+	if c.Query("archived") == "true" {
+		if role != "admin" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
+		query = query.Unscoped().Where("attendances.deleted_at IS NOT NULL")
+	}
 	if role == "employee" {
 		employeeID, ok := c.Get(middleware.ContextEmployeeID)
 		if !ok || employeeID == "" {
@@ -133,26 +160,58 @@ func (h *AttendanceHandler) List(c *gin.Context) {
 			return
 		}
 		h.closeExpiredAttendance(&id)
-		var records []models.Attendance
-		if err := h.DB.Preload("Breaks", func(db *gorm.DB) *gorm.DB {
-			return db.Order("created_at asc")
-		}).Where("employee_id = ?", id).Order("created_at desc").Find(&records).Error; err != nil {
+		query = query.Where("attendances.employee_id = ?", id)
+	} else {
+		h.closeExpiredAttendance(nil)
+		reports, scoped, err := managerScope(h.DB, c)
+		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load attendance"})
 			return
 		}
-		c.JSON(http.StatusOK, records)
+		if scoped {
+			query = query.Where("attendances.employee_id IN ?", reports)
+		}
+		if value := c.Query("employeeId"); value != "" {
+			id, err := uuid.Parse(value)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employeeId"})
+				return
+			}
+			query = query.Where("attendances.employee_id = ?", id)
+		}
+	}
+	if !from.IsZero() {
+		query = query.Where("attendances.check_in >= ?", from)
+	}
+	if !to.IsZero() {
+		query = query.Where("attendances.check_in < ?", to)
+	}
+	switch c.Query("open") {
+	case "":
+	case "true":
+		query = query.Where("attendances.check_out IS NULL")
+	case "false":
+		query = query.Where("attendances.check_out IS NOT NULL")
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid open"})
 		return
 	}
+	query = query.Session(&gorm.Session{})
 
-	h.closeExpiredAttendance(nil)
-	var records []models.Attendance
-	if err := h.DB.Preload("Breaks", func(db *gorm.DB) *gorm.DB {
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load attendance"})
+		return
+	}
+
+	records := []models.Attendance{}
+	if err := params.apply(query, "attendances").Preload("Breaks", func(db *gorm.DB) *gorm.DB {
 		return db.Order("created_at asc")
-	}).Order("created_at desc").Find(&records).Error; err != nil {
+	}).Find(&records).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load attendance"})
 		return
 	}
-	c.JSON(http.StatusOK, records)
+	c.JSON(http.StatusOK, pageResponse(records, total, params))
 }
 
 func (h *AttendanceHandler) CheckIn(c *gin.Context) {
@@ -180,6 +239,20 @@ func (h *AttendanceHandler) CheckIn(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employeeId"})
 		return
 	}
+	if status, message := employeeAccessError(h.DB, c, employeeID); message != "" {
+		c.JSON(status, gin.H{"error": message})
+		return
+	}
+
+	var employee models.Employee
+	if err := h.DB.First(&employee, "id = ?", employeeID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+		return
+	}
+	if employee.Status == employeeStatusTerminated {
+		c.JSON(http.StatusConflict, gin.H{"error": "employee is terminated"})
+		return
+	}
 
 	checkInTime := time.Now()
 	if role != "employee" && req.CheckInAt != "" {
@@ -194,6 +267,12 @@ func (h *AttendanceHandler) CheckIn(c *gin.Context) {
 		}
 		checkInTime = parsed
 	}
+	if payrollPeriodLockedResponse(c, h.DB, checkInTime, checkInTime) {
+		return
+	}
+	if timesheetLockedResponse(c, h.DB, employeeID, checkInTime) {
+		return
+	}
 
 	var openRecord models.Attendance
 	if err := h.DB.Where("employee_id = ? AND check_out IS NULL", employeeID).
@@ -228,7 +307,12 @@ func (h *AttendanceHandler) CheckIn(c *gin.Context) {
 		CheckIn:    checkInTime,
 	}
 
-	if err := h.DB.Create(&record).Error; err != nil {
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&record).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionCreate, "attendance", record.ID, nil, record)
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "checkin failed"})
 		return
 	}
@@ -279,12 +363,9 @@ func (h *AttendanceHandler) CheckOut(c *gin.Context) {
 		}
 	}
 
-	if role == "employee" {
-		employeeID, _ := c.Get(middleware.ContextEmployeeID)
-		if employeeID != nil && record.EmployeeID.String() != employeeID.(string) {
-			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
-			return
-		}
+	if status, message := employeeAccessError(h.DB, c, record.EmployeeID); message != "" {
+		c.JSON(status, gin.H{"error": message})
+		return
 	}
 
 	checkOutTime := time.Now()
@@ -294,35 +375,41 @@ func (h *AttendanceHandler) CheckOut(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid checkOutAt"})
 			return
 		}
-		if parsed.After(time.Now()) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "checkOutAt cannot be in the future"})
-			return
-		}
 		checkOutTime = parsed
 	}
-	if checkOutTime.Before(record.CheckIn) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "checkOutAt cannot be before checkIn"})
+	if message := checkOutError(record.CheckIn, checkOutTime, time.Now()); message != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
 		return
 	}
 	maxClose := record.CheckIn.Add(time.Duration(maxShiftHours) * time.Hour)
 	if checkOutTime.After(maxClose) {
 		checkOutTime = maxClose
 	}
+	if payrollPeriodLockedResponse(c, h.DB, record.CheckIn, record.CheckIn) {
+		return
+	}
+	if timesheetLockedResponse(c, h.DB, record.EmployeeID, record.CheckIn) {
+		return
+	}
 
-	for index := range record.Breaks {
-		if record.Breaks[index].BreakEnd == nil {
+	before := record
+	before.Breaks = append([]models.AttendanceBreak(nil), record.Breaks...)
+	record.CheckOut = &checkOutTime
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		for index := range record.Breaks {
+			if record.Breaks[index].BreakEnd != nil && !record.Breaks[index].BreakEnd.After(checkOutTime) {
+				continue
+			}
 			record.Breaks[index].BreakEnd = &checkOutTime
-			_ = h.DB.Save(&record.Breaks[index]).Error
-			continue
+			if err := tx.Save(&record.Breaks[index]).Error; err != nil {
+				return err
+			}
 		}
-		if record.Breaks[index].BreakEnd.After(checkOutTime) {
-			record.Breaks[index].BreakEnd = &checkOutTime
-			_ = h.DB.Save(&record.Breaks[index]).Error
+		if err := tx.Save(&record).Error; err != nil {
+			return err
 		}
-	}
-
-	record.CheckOut = &checkOutTime
-	if err := h.DB.Save(&record).Error; err != nil {
+		return recordAudit(tx, c, auditActionUpdate, "attendance", record.ID, before, record)
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "checkout failed"})
 		return
 	}
@@ -353,6 +440,16 @@ func (h *AttendanceHandler) BreakStart(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid attendance request"})
 		return
 	}
+	if status, message := employeeAccessError(h.DB, c, record.EmployeeID); message != "" {
+		c.JSON(status, gin.H{"error": message})
+		return
+	}
+	if payrollPeriodLockedResponse(c, h.DB, record.CheckIn, record.CheckIn) {
+		return
+	}
+	if timesheetLockedResponse(c, h.DB, record.EmployeeID, record.CheckIn) {
+		return
+	}
 
 	for _, br := range record.Breaks {
 		if br.BreakEnd == nil {
@@ -373,7 +470,12 @@ func (h *AttendanceHandler) BreakStart(c *gin.Context) {
 		AttendanceID: record.ID,
 		BreakStart:   now,
 	}
-	if err := h.DB.Create(&newBreak).Error; err != nil {
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&newBreak).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionCreate, "attendance_break", newBreak.ID, nil, newBreak)
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "break start failed"})
 		return
 	}
@@ -397,6 +499,16 @@ func (h *AttendanceHandler) BreakEnd(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid attendance request"})
 		return
 	}
+	if status, message := employeeAccessError(h.DB, c, record.EmployeeID); message != "" {
+		c.JSON(status, gin.H{"error": message})
+		return
+	}
+	if payrollPeriodLockedResponse(c, h.DB, record.CheckIn, record.CheckIn) {
+		return
+	}
+	if timesheetLockedResponse(c, h.DB, record.EmployeeID, record.CheckIn) {
+		return
+	}
 
 	var openBreak models.AttendanceBreak
 	if err := h.DB.Where("attendance_id = ? AND break_end IS NULL", record.ID).
@@ -405,12 +517,18 @@ func (h *AttendanceHandler) BreakEnd(c *gin.Context) {
 		return
 	}
 
+	before := openBreak
 	now := time.Now()
 	if now.Before(openBreak.BreakStart) {
 		now = openBreak.BreakStart
 	}
 	openBreak.BreakEnd = &now
-	if err := h.DB.Save(&openBreak).Error; err != nil {
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&openBreak).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionUpdate, "attendance_break", openBreak.ID, before, openBreak)
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "break end failed"})
 		return
 	}
@@ -447,10 +565,6 @@ func (h *AttendanceHandler) AddManualBreak(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid breakEndAt"})
 		return
 	}
-	if !breakEnd.After(breakStart) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "breakEndAt must be after breakStartAt"})
-		return
-	}
 
 	var record models.Attendance
 	if err := h.DB.Preload("Breaks", func(db *gorm.DB) *gorm.DB {
@@ -459,9 +573,15 @@ func (h *AttendanceHandler) AddManualBreak(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "attendance not found"})
 		return
 	}
+	if _, status, message := accessibleEmployeeByID(h.DB, c, record.EmployeeID, true); message != "" {
+		c.JSON(status, gin.H{"error": message})
+		return
+	}
 
-	if breakStart.Before(record.CheckIn) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "break cannot start before check-in"})
+	if payrollPeriodLockedResponse(c, h.DB, record.CheckIn, record.CheckIn) {
+		return
+	}
+	if timesheetLockedResponse(c, h.DB, record.EmployeeID, record.CheckIn) {
 		return
 	}
 
@@ -469,29 +589,22 @@ func (h *AttendanceHandler) AddManualBreak(c *gin.Context) {
 	if record.CheckOut != nil {
 		latestAllowed = *record.CheckOut
 	}
-	if breakEnd.After(latestAllowed) {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "break cannot end after shift end"})
+	if status, message := breakError(record.CheckIn, latestAllowed, record.Breaks, breakStart, breakEnd); message != "" {
+		c.JSON(status, gin.H{"error": message})
 		return
 	}
 
-	for _, existing := range record.Breaks {
-		if existing.BreakEnd == nil {
-			c.JSON(http.StatusConflict, gin.H{"error": "active break exists, end it first"})
-			return
-		}
-		existingEnd := *existing.BreakEnd
-		if breakStart.Before(existingEnd) && breakEnd.After(existing.BreakStart) {
-			c.JSON(http.StatusConflict, gin.H{"error": "break overlaps existing break"})
-			return
-		}
-	}
-
 	newBreak := models.AttendanceBreak{
 		AttendanceID: record.ID,
 		BreakStart:   breakStart,
 		BreakEnd:     &breakEnd,
 	}
-	if err := h.DB.Create(&newBreak).Error; err != nil {
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&newBreak).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionCreate, "attendance_break", newBreak.ID, nil, newBreak)
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "manual break add failed"})
 		return
 	}
@@ -499,6 +612,42 @@ func (h *AttendanceHandler) AddManualBreak(c *gin.Context) {
 	c.JSON(http.StatusCreated, newBreak)
 }
 
+// checkOutError validates a check-out time for a record checked in at
+// checkIn.
+func checkOutError(checkIn, checkOut, now time.Time) string {
+	if checkOut.After(now) {
+		return "checkOutAt cannot be in the future"
+	}
+	if checkOut.Before(checkIn) {
+		return "checkOutAt cannot be before checkIn"
+	}
+	return ""
+}
+
+// breakError validates a break from start to end for a record checked in at
+// checkIn and ending at shiftEnd, or now while open, against the breaks
+// already recorded.
+func breakError(checkIn, shiftEnd time.Time, existing []models.AttendanceBreak, start, end time.Time) (int, string) {
+	if !end.After(start) {
+		return http.StatusBadRequest, "breakEndAt must be after breakStartAt"
+	}
+	if start.Before(checkIn) {
+		return http.StatusBadRequest, "break cannot start before check-in"
+	}
+	if end.After(shiftEnd) {
+		return http.StatusBadRequest, "break cannot end after shift end"
+	}
+	for _, other := range existing {
+		if other.BreakEnd == nil {
+			return http.StatusConflict, "active break exists, end it first"
+		}
+		if start.Before(*other.BreakEnd) && end.After(other.BreakStart) {
+			return http.StatusConflict, "break overlaps existing break"
+		}
+	}
+	return 0, ""
+}
+
 func (h *AttendanceHandler) autoCloseIfExpired(record *models.Attendance) bool {
 	if record.CheckOut != nil {
 		return true
@@ -547,6 +696,35 @@ func (h *AttendanceHandler) closeExpiredAttendance(employeeID *uuid.UUID) {
 	}
 }
 
+// closeOpenAttendance checks out every open record of an employee at the
+// given time, capped at the maximum shift length, and ends open breaks.
+func closeOpenAttendance(tx *gorm.DB, employeeID uuid.UUID, at time.Time) error {
+	var records []models.Attendance
+	if err := tx.Where("employee_id = ? AND check_out IS NULL", employeeID).Find(&records).Error; err != nil {
+		return err
+	}
+	for i := range records {
+		closeAt := at
+		if maxClose := records[i].CheckIn.Add(time.Duration(maxShiftHours) * time.Hour); closeAt.After(maxClose) {
+			closeAt = maxClose
+		}
+		if closeAt.Before(records[i].CheckIn) {
+			closeAt = records[i].CheckIn
+		}
+		if err := tx.Model(&models.AttendanceBreak{}).
+			Where("attendance_id = ? AND break_end IS NULL", records[i].ID).
+			Update("break_end", closeAt).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Attendance{}).
+			Where("id = ?", records[i].ID).
+			Update("check_out", closeAt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (h *AttendanceHandler) Delete(c *gin.Context) {
 	role, _ := c.Get(middleware.ContextRole)
 	if role == "employee" {
@@ -559,7 +737,28 @@ func (h *AttendanceHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.DB.Delete(&models.Attendance{}, "id = ?", attendanceID).Error; err != nil {
+	var record models.Attendance
+	if err := h.DB.Preload("Breaks").First(&record, "id = ?", attendanceID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "attendance not found"})
+		return
+	}
+	if _, status, message := accessibleEmployeeByID(h.DB, c, record.EmployeeID, true); message != "" {
+		c.JSON(status, gin.H{"error": message})
+		return
+	}
+	if payrollPeriodLockedResponse(c, h.DB, record.CheckIn, record.CheckIn) {
+		return
+	}
+	if timesheetLockedResponse(c, h.DB, record.EmployeeID, record.CheckIn) {
+		return
+	}
+
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.Attendance{}, "id = ?", attendanceID).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionDelete, "attendance", record.ID, record, nil)
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "delete failed"})
 		return
 	}
@@ -567,6 +766,50 @@ func (h *AttendanceHandler) Delete(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
 }
 
+func (h *AttendanceHandler) Restore(c *gin.Context) {
+	attendanceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var record models.Attendance
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Preload("Breaks").Where("deleted_at IS NOT NULL").First(&record, "id = ?", attendanceID).Error; err != nil {
+			return err
+		}
+		if locked, err := payrollPeriodLocked(tx, record.CheckIn, record.CheckIn); err != nil {
+			return err
+		} else if locked {
+			return errPayrollPeriodLocked
+		}
+		if locked, err := timesheetLocked(tx, record.EmployeeID, record.CheckIn); err != nil {
+			return err
+		} else if locked {
+			return errTimesheetLocked
+		}
+		before := record
+		if err := tx.Unscoped().Model(&models.Attendance{}).Where("id = ?", record.ID).Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		record.DeletedAt = gorm.DeletedAt{}
+		return recordAudit(tx, c, auditActionRestore, "attendance", record.ID, before, record)
+	}); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "archived attendance not found"})
+			return
+		}
+		if err == errTimesheetLocked || err == errPayrollPeriodLocked {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "restore failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, record)
+}
+
 func (h *AttendanceHandler) DeleteByEmployee(c *gin.Context) {
 	role, _ := c.Get(middleware.ContextRole)
 	if role == "employee" {
@@ -579,8 +822,42 @@ func (h *AttendanceHandler) DeleteByEmployee(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employeeId"})
 		return
 	}
+	if _, status, message := accessibleEmployeeByID(h.DB, c, employeeID, true); message != "" {
+		c.JSON(status, gin.H{"error": message})
+		return
+	}
 
-	if err := h.DB.Where("employee_id = ?", employeeID).Delete(&models.Attendance{}).Error; err != nil {
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		var records []models.Attendance
+		if err := tx.Preload("Breaks").Where("employee_id = ?", employeeID).Find(&records).Error; err != nil {
+			return err
+		}
+		for _, record := range records {
+			if locked, err := payrollPeriodLocked(tx, record.CheckIn, record.CheckIn); err != nil {
+				return err
+			} else if locked {
+				return errPayrollPeriodLocked
+			}
+			if locked, err := timesheetLocked(tx, record.EmployeeID, record.CheckIn); err != nil {
+				return err
+			} else if locked {
+				return errTimesheetLocked
+			}
+		}
+		if err := tx.Where("employee_id = ?", employeeID).Delete(&models.Attendance{}).Error; err != nil {
+			return err
+		}
+		for _, record := range records {
+			if err := recordAudit(tx, c, auditActionDelete, "attendance", record.ID, record, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		if err == errTimesheetLocked || err == errPayrollPeriodLocked {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "delete failed"})
 		return
 	}
@@ -0,0 +1,434 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"erp-backend/internal/middleware"
+	"erp-backend/internal/models"
+)
+
+const (
+	correctionStatusPending   = "pending"
+	correctionStatusApproved  = "approved"
+	correctionStatusRejected  = "rejected"
+	correctionStatusCancelled = "cancelled"
+)
+
+var errCorrectionNotPending = errors.New("correction is not pending")
+
+type correctionBreakRequest struct {
+	BreakStartAt string `json:"breakStartAt"`
+	BreakEndAt   string `json:"breakEndAt"`
+}
+
+type createCorrectionRequest struct {
+	AttendanceID string                    `json:"attendanceId" binding:"required"`
+	CheckInAt    string                    `json:"checkInAt"`
+	CheckOutAt   string                    `json:"checkOutAt" binding:"required"`
+	Breaks       *[]correctionBreakRequest `json:"breaks"`
+	Reason       string                    `json:"reason" binding:"required"`
+}
+
+type reviewCorrectionRequest struct {
+	Comment string `json:"comment"`
+}
+
+// correctedBreaks validates the proposed times of correction for record with
+// the rules of CheckOut and AddManualBreak, and returns the breaks the record
+// ends up with. Kept breaks are closed and clamped at the new check-out the
+// way CheckOut does.
+func correctedBreaks(db *gorm.DB, correction models.AttendanceCorrection, record models.Attendance, now time.Time) ([]models.AttendanceBreak, int, string) {
+	if correction.CheckIn.After(now) {
+		return nil, http.StatusBadRequest, "checkInAt cannot be in the future"
+	}
+	if message := checkOutError(correction.CheckIn, correction.CheckOut, now); message != "" {
+		return nil, http.StatusBadRequest, message
+	}
+	if correction.CheckOut.Sub(correction.CheckIn) > time.Duration(maxShiftHours)*time.Hour {
+		return nil, http.StatusBadRequest, fmt.Sprintf("shift cannot be longer than %d hours", maxShiftHours)
+	}
+
+	var overlapping int64
+	if err := db.Model(&models.Attendance{}).
+		Where("employee_id = ? AND id <> ? AND check_in < ? AND (check_out IS NULL OR check_out > ?)",
+			record.EmployeeID, record.ID, correction.CheckOut, correction.CheckIn).
+		Count(&overlapping).Error; err != nil {
+		return nil, http.StatusInternalServerError, "could not check attendance"
+	}
+	if overlapping > 0 {
+		return nil, http.StatusConflict, "corrected times overlap another attendance record"
+	}
+
+	proposed := []models.AttendanceBreak{}
+	if correction.ReplaceBreaks {
+		for _, br := range correction.Breaks {
+			end := br.BreakEnd
+			proposed = append(proposed, models.AttendanceBreak{AttendanceID: record.ID, BreakStart: br.BreakStart, BreakEnd: &end})
+		}
+	} else {
+		for _, br := range record.Breaks {
+			end := correction.CheckOut
+			if br.BreakEnd != nil && br.BreakEnd.Before(end) {
+				end = *br.BreakEnd
+			}
+			br.BreakEnd = &end
+			proposed = append(proposed, br)
+		}
+	}
+
+	breaks := make([]models.AttendanceBreak, 0, len(proposed))
+	for _, br := range proposed {
+		if status, message := breakError(correction.CheckIn, correction.CheckOut, breaks, br.BreakStart, *br.BreakEnd); message != "" {
+			return nil, status, message
+		}
+		breaks = append(breaks, br)
+	}
+	return breaks, 0, ""
+}
+
+// correctionLockedResponse refuses corrections moving attendance into or out
+// of a finalized payroll period or an approved timesheet.
+func correctionLockedResponse(c *gin.Context, db *gorm.DB, record models.Attendance, checkIn time.Time) bool {
+	for _, at := range []time.Time{record.CheckIn, checkIn} {
+		if payrollPeriodLockedResponse(c, db, at, at) || timesheetLockedResponse(c, db, record.EmployeeID, at) {
+			return true
+		}
+	}
+	return false
+}
+
+var correctionSortColumns = map[string]string{
+	"createdAt": "created_at",
+	"checkIn":   "check_in",
+}
+
+func (h *AttendanceHandler) ListCorrections(c *gin.Context) {
+	params, message := parseListParams(c, correctionSortColumns, "createdAt")
+	if message != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return
+	}
+
+	query := h.DB.Model(&models.AttendanceCorrection{})
+	role, _ := c.Get(middleware.ContextRole)
+	if role == "employee" {
+		employeeID, ok := contextEmployeeID(c)
+		if !ok {
+			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
+		query = query.Where("employee_id = ?", employeeID)
+	}
+	reports, scoped, err := managerScope(h.DB, c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load corrections"})
+		return
+	}
+	if scoped {
+		query = query.Where("employee_id IN ?", reports)
+	}
+	if value := c.Query("employeeId"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employeeId"})
+			return
+		}
+		query = query.Where("employee_id = ?", id)
+	}
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load corrections"})
+		return
+	}
+	corrections := []models.AttendanceCorrection{}
+	if err := params.apply(query, "attendance_corrections").Preload("Breaks", func(db *gorm.DB) *gorm.DB {
+		return db.Order("break_start asc")
+	}).Find(&corrections).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load corrections"})
+		return
+	}
+	c.JSON(http.StatusOK, pageResponse(corrections, total, params))
+}
+
+// CreateCorrection files proposed times for an attendance record. Leaving
+// out breaks keeps those of the record; an empty list removes them. The
+// proposal is validated now and again when it is approved.
+func (h *AttendanceHandler) CreateCorrection(c *gin.Context) {
+	var req createCorrectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.Reason) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "attendanceId, checkOutAt and reason required"})
+		return
+	}
+	attendanceID, err := uuid.Parse(req.AttendanceID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid attendanceId"})
+		return
+	}
+
+	var record models.Attendance
+	if err := h.DB.Preload("Breaks", func(db *gorm.DB) *gorm.DB {
+		return db.Order("break_start asc")
+	}).First(&record, "id = ?", attendanceID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "attendance not found"})
+		return
+	}
+	if _, status, message := accessibleEmployeeByID(h.DB, c, record.EmployeeID, false); message != "" {
+		c.JSON(status, gin.H{"error": message})
+		return
+	}
+
+	correction := models.AttendanceCorrection{
+		EmployeeID:   record.EmployeeID,
+		AttendanceID: record.ID,
+		CheckIn:      record.CheckIn,
+		Reason:       strings.TrimSpace(req.Reason),
+		Status:       correctionStatusPending,
+		RequestedBy:  actorUserID(c),
+		Breaks:       []models.AttendanceCorrectionBreak{},
+	}
+	if req.CheckInAt != "" {
+		if correction.CheckIn, err = parseAdminTime(req.CheckInAt); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid checkInAt"})
+			return
+		}
+	}
+	if correction.CheckOut, err = parseAdminTime(req.CheckOutAt); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid checkOutAt"})
+		return
+	}
+	if req.Breaks != nil {
+		correction.ReplaceBreaks = true
+		for _, br := range *req.Breaks {
+			start, err := parseAdminTime(br.BreakStartAt)
+			if err != nil || br.BreakStartAt == "" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid breakStartAt"})
+				return
+			}
+			end, err := parseAdminTime(br.BreakEndAt)
+			if err != nil || br.BreakEndAt == "" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid breakEndAt"})
+				return
+			}
+			correction.Breaks = append(correction.Breaks, models.AttendanceCorrectionBreak{BreakStart: start, BreakEnd: end})
+		}
+	}
+
+	if correctionLockedResponse(c, h.DB, record, correction.CheckIn) {
+		return
+	}
+	if _, status, message := correctedBreaks(h.DB, correction, record, time.Now()); message != "" {
+		c.JSON(status, gin.H{"error": message})
+		return
+	}
+	var pending int64
+	if err := h.DB.Model(&models.AttendanceCorrection{}).
+		Where("attendance_id = ? AND status = ?", record.ID, correctionStatusPending).
+		Count(&pending).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "create failed"})
+		return
+	}
+	if pending > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "a correction for this attendance is already pending"})
+		return
+	}
+
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&correction).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionCreate, "attendance_correction", correction.ID, nil, correction)
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "create failed"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, correction)
+}
+
+// loadPendingCorrection loads the correction named by the id parameter for a
+// reviewer who may manage its employee.
+func (h *AttendanceHandler) loadPendingCorrection(c *gin.Context, manage bool) (models.AttendanceCorrection, bool) {
+	var correction models.AttendanceCorrection
+	correctionID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return correction, false
+	}
+	if err := h.DB.Preload("Breaks", func(db *gorm.DB) *gorm.DB {
+		return db.Order("break_start asc")
+	}).First(&correction, "id = ?", correctionID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "correction not found"})
+		return correction, false
+	}
+	if _, status, message := accessibleEmployeeByID(h.DB, c, correction.EmployeeID, manage); message != "" {
+		c.JSON(status, gin.H{"error": message})
+		return correction, false
+	}
+	if correction.Status != correctionStatusPending {
+		c.JSON(http.StatusConflict, gin.H{"error": "correction is " + correction.Status})
+		return correction, false
+	}
+	return correction, true
+}
+
+// ApproveCorrection applies the proposed times to the attendance record.
+func (h *AttendanceHandler) ApproveCorrection(c *gin.Context) {
+	var req reviewCorrectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	correction, ok := h.loadPendingCorrection(c, true)
+	if !ok {
+		return
+	}
+
+	var record models.Attendance
+	if err := h.DB.Preload("Breaks", func(db *gorm.DB) *gorm.DB {
+		return db.Order("break_start asc")
+	}).First(&record, "id = ?", correction.AttendanceID).Error; err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "attendance no longer exists"})
+		return
+	}
+	if correctionLockedResponse(c, h.DB, record, correction.CheckIn) {
+		return
+	}
+	breaks, status, message := correctedBreaks(h.DB, correction, record, time.Now())
+	if message != "" {
+		c.JSON(status, gin.H{"error": message})
+		return
+	}
+
+	before := correction
+	beforeRecord := record
+	now := time.Now()
+	correction.Status = correctionStatusApproved
+	correction.ReviewComment = strings.TrimSpace(req.Comment)
+	correction.ReviewedBy = actorUserID(c)
+	correction.ReviewedAt = &now
+
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.AttendanceCorrection{}).
+			Where("id = ? AND status = ?", correction.ID, correctionStatusPending).
+			Updates(map[string]any{
+				"status":         correction.Status,
+				"review_comment": correction.ReviewComment,
+				"reviewed_by":    correction.ReviewedBy,
+				"reviewed_at":    correction.ReviewedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errCorrectionNotPending
+		}
+
+		if correction.ReplaceBreaks {
+			if err := tx.Where("attendance_id = ?", record.ID).Delete(&models.AttendanceBreak{}).Error; err != nil {
+				return err
+			}
+		}
+		for index := range breaks {
+			if err := tx.Save(&breaks[index]).Error; err != nil {
+				return err
+			}
+		}
+		checkOut := correction.CheckOut
+		if err := tx.Model(&models.Attendance{}).Where("id = ?", record.ID).
+			Updates(map[string]any{"check_in": correction.CheckIn, "check_out": checkOut}).Error; err != nil {
+			return err
+		}
+		record.CheckIn = correction.CheckIn
+		record.CheckOut = &checkOut
+		record.Breaks = breaks
+
+		if err := recordAudit(tx, c, auditActionUpdate, "attendance", record.ID, beforeRecord, record); err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionUpdate, "attendance_correction", correction.ID, before, correction)
+	}); err != nil {
+		if err == errCorrectionNotPending {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "approve failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, correction)
+}
+
+// closeCorrection moves a pending correction to status without touching the
+// attendance record.
+func (h *AttendanceHandler) closeCorrection(c *gin.Context, correction models.AttendanceCorrection, status string, comment string) {
+	before := correction
+	now := time.Now()
+	correction.Status = status
+	correction.ReviewComment = comment
+	correction.ReviewedBy = actorUserID(c)
+	correction.ReviewedAt = &now
+
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.AttendanceCorrection{}).
+			Where("id = ? AND status = ?", correction.ID, correctionStatusPending).
+			Updates(map[string]any{
+				"status":         correction.Status,
+				"review_comment": correction.ReviewComment,
+				"reviewed_by":    correction.ReviewedBy,
+				"reviewed_at":    correction.ReviewedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errCorrectionNotPending
+		}
+		return recordAudit(tx, c, auditActionUpdate, "attendance_correction", correction.ID, before, correction)
+	}); err != nil {
+		if err == errCorrectionNotPending {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, correction)
+}
+
+func (h *AttendanceHandler) RejectCorrection(c *gin.Context) {
+	var req reviewCorrectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.Comment) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "comment required"})
+		return
+	}
+	correction, ok := h.loadPendingCorrection(c, true)
+	if !ok {
+		return
+	}
+	h.closeCorrection(c, correction, correctionStatusRejected, strings.TrimSpace(req.Comment))
+}
+
+// CancelCorrection withdraws a pending correction. Employees can cancel
+// their own.
+func (h *AttendanceHandler) CancelCorrection(c *gin.Context) {
+	correction, ok := h.loadPendingCorrection(c, false)
+	if !ok {
+		return
+	}
+	h.closeCorrection(c, correction, correctionStatusCancelled, "")
+}
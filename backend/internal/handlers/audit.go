@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"erp-backend/internal/middleware"
+	"erp-backend/internal/models"
+)
+
+type AuditHandler struct {
+	DB *gorm.DB
+}
+
+const (
+	auditActionCreate  = "create"
+	auditActionUpdate  = "update"
+	auditActionDelete  = "delete"
+	auditActionRestore = "restore"
+
+	// auditMaxValueLength caps string values kept in a diff so large payloads
+	// such as logo data URLs do not bloat the log.
+	auditMaxValueLength = 1024
+)
+
+type auditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+func NewAuditHandler(db *gorm.DB) *AuditHandler {
+	return &AuditHandler{DB: db}
+}
+
+func auditFields(value interface{}) map[string]interface{} {
+	fields := map[string]interface{}{}
+	if value == nil {
+		return fields
+	}
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fields
+	}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return map[string]interface{}{"value": string(raw)}
+	}
+	return fields
+}
+
+func auditValue(value interface{}) interface{} {
+	if text, ok := value.(string); ok && len(text) > auditMaxValueLength {
+		return fmt.Sprintf("<%d bytes>", len(text))
+	}
+	return value
+}
+
+// auditDiff returns the fields that differ between the JSON representations
+// of before and after. Either side may be nil for creates and deletes.
+func auditDiff(before, after interface{}) map[string]auditChange {
+	beforeFields := auditFields(before)
+	afterFields := auditFields(after)
+
+	keys := map[string]bool{}
+	for key := range beforeFields {
+		keys[key] = true
+	}
+	for key := range afterFields {
+		keys[key] = true
+	}
+
+	diff := map[string]auditChange{}
+	for key := range keys {
+		if key == "updatedAt" {
+			continue
+		}
+		b, a := beforeFields[key], afterFields[key]
+		if reflect.DeepEqual(b, a) {
+			continue
+		}
+		diff[key] = auditChange{Before: auditValue(b), After: auditValue(a)}
+	}
+	return diff
+}
+
+// recordAudit stores who performed action on the entity and what changed.
+// Pass the transaction as db when the change is transactional so the event
+// is only kept if the change commits. Background jobs pass a nil c and are
+// recorded with the system role.
+func recordAudit(db *gorm.DB, c *gin.Context, action, entityType string, entityID interface{}, before, after interface{}) error {
+	changes, err := json.Marshal(auditDiff(before, after))
+	if err != nil {
+		return err
+	}
+
+	event := models.AuditEvent{
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   fmt.Sprint(entityID),
+		Changes:    changes,
+	}
+	if c != nil {
+		if value, ok := c.Get(middleware.ContextUserID); ok {
+			if id, err := uuid.Parse(fmt.Sprint(value)); err == nil {
+				event.ActorUserID = &id
+			}
+		}
+		if value, ok := c.Get(middleware.ContextRole); ok {
+			event.ActorRole = fmt.Sprint(value)
+		}
+		event.IP = c.ClientIP()
+	} else {
+		event.ActorRole = "system"
+	}
+
+	if err := db.Create(&event).Error; err != nil {
+		log.Printf("audit %s %s %s: %v", action, entityType, event.EntityID, err)
+		return err
+	}
+	return nil
+}
+
+// auditPasswordChanged wraps a user so the otherwise hidden password change
+// shows up in the diff without recording the hash.
+func auditPasswordChanged(user models.User) map[string]interface{} {
+	fields := auditFields(user)
+	fields["password"] = "changed"
+	return fields
+}
+
+var auditSortColumns = map[string]string{
+	"createdAt":  "created_at",
+	"entityType": "entity_type",
+	"action":     "action",
+}
+
+func (h *AuditHandler) List(c *gin.Context) {
+	params, message := parseListParams(c, auditSortColumns, "createdAt")
+	if message != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return
+	}
+	from, to, message := parseDateRange(c)
+	if message != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return
+	}
+
+	query := h.DB.Model(&models.AuditEvent{})
+	if value := c.Query("entityType"); value != "" {
+		query = query.Where("audit_events.entity_type = ?", value)
+	}
+	if value := c.Query("entityId"); value != "" {
+		query = query.Where("audit_events.entity_id = ?", value)
+	}
+	if value := c.Query("actorId"); value != "" {
+		actorID, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid actorId"})
+			return
+		}
+		query = query.Where("audit_events.actor_user_id = ?", actorID)
+	}
+	if value := c.Query("action"); value != "" {
+		query = query.Where("audit_events.action IN ?", strings.Split(value, ","))
+	}
+	if !from.IsZero() {
+		query = query.Where("audit_events.created_at >= ?", from)
+	}
+	if !to.IsZero() {
+		query = query.Where("audit_events.created_at < ?", to)
+	}
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load audit events"})
+		return
+	}
+
+	events := []models.AuditEvent{}
+	if err := params.apply(query, "audit_events").Find(&events).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load audit events"})
+		return
+	}
+	c.JSON(http.StatusOK, pageResponse(events, total, params))
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"log"
 	"net/http"
 	"strings"
@@ -74,6 +75,16 @@ func NewAuthHandler(db *gorm.DB, cfg config.Config) *AuthHandler {
 	return &AuthHandler{DB: db, Cfg: cfg}
 }
 
+func smtpConfig(cfg config.Config) email.Config {
+	return email.Config{
+		Host:     cfg.SmtpHost,
+		Port:     cfg.SmtpPort,
+		Username: cfg.SmtpUser,
+		Password: cfg.SmtpPass,
+		From:     cfg.SmtpFrom,
+	}
+}
+
 func (h *AuthHandler) RegisterStart(c *gin.Context) {
 	var req registerStartRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -109,13 +120,7 @@ func (h *AuthHandler) RegisterStart(c *gin.Context) {
 		return
 	}
 
-	smtpCfg := email.Config{
-		Host:     h.Cfg.SmtpHost,
-		Port:     h.Cfg.SmtpPort,
-		Username: h.Cfg.SmtpUser,
-		Password: h.Cfg.SmtpPass,
-		From:     h.Cfg.SmtpFrom,
-	}
+	smtpCfg := smtpConfig(h.Cfg)
 	if err := email.SendOTP(smtpCfg, req.Email, code); err != nil {
 		log.Printf("smtp send error: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "email failed"})
@@ -165,7 +170,12 @@ func (h *AuthHandler) RegisterVerify(c *gin.Context) {
 		Name:         req.Name,
 		Role:         role,
 	}
-	if err := h.DB.Create(&user).Error; err != nil {
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionCreate, "user", user.ID, nil, user)
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "user creation failed"})
 		return
 	}
@@ -236,6 +246,18 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid credentials"})
 		return
 	}
+	if user.DisabledAt != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "account disabled"})
+		return
+	}
+	if user.EmployeeID != nil {
+		var employee models.Employee
+		if err := h.DB.Unscoped().First(&employee, "id = ?", *user.EmployeeID).Error; err == nil &&
+			employee.Status == employeeStatusTerminated {
+			c.JSON(http.StatusForbidden, gin.H{"error": "employment terminated"})
+			return
+		}
+	}
 
 	employeeID := ""
 	if user.EmployeeID != nil {
@@ -295,13 +317,7 @@ func (h *AuthHandler) ForgotPasswordStart(c *gin.Context) {
 		return
 	}
 
-	smtpCfg := email.Config{
-		Host:     h.Cfg.SmtpHost,
-		Port:     h.Cfg.SmtpPort,
-		Username: h.Cfg.SmtpUser,
-		Password: h.Cfg.SmtpPass,
-		From:     h.Cfg.SmtpFrom,
-	}
+	smtpCfg := smtpConfig(h.Cfg)
 	if err := email.SendOTP(smtpCfg, normalizedEmail, code); err != nil {
 		log.Printf("smtp send error: %v", err)
 		if strings.EqualFold(h.Cfg.AppEnv, "production") {
@@ -356,6 +372,9 @@ func (h *AuthHandler) ForgotPasswordVerify(c *gin.Context) {
 		if err := tx.Model(&models.OTP{}).Where("id = ?", otp.ID).Update("used_at", now).Error; err != nil {
 			return err
 		}
+		if err := recordAudit(tx, c, auditActionUpdate, "user", user.ID, user, auditPasswordChanged(user)); err != nil {
+			return err
+		}
 		return tx.Model(&models.RefreshToken{}).
 			Where("user_id = ? AND revoked_at IS NULL", user.ID).
 			Update("revoked_at", now).Error
@@ -381,7 +400,7 @@ func (h *AuthHandler) Refresh(c *gin.Context) {
 	}
 
 	var user models.User
-	if err := h.DB.First(&user, "id = ?", token.UserID).Error; err != nil {
+	if err := h.DB.First(&user, "id = ?", token.UserID).Error; err != nil || user.DisabledAt != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid refresh"})
 		return
 	}
@@ -423,22 +442,38 @@ func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 		return
 	}
 
+	before := user
 	user.Name = req.Name
 	user.AvatarURL = strings.TrimSpace(req.AvatarURL)
-	if err := h.DB.Save(&user).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed"})
-		return
-	}
-
 	phone := strings.TrimSpace(req.Phone)
 	position := strings.TrimSpace(req.Position)
-	if user.EmployeeID != nil {
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&user).Error; err != nil {
+			return err
+		}
+		if err := recordAudit(tx, c, auditActionUpdate, "user", user.ID, before, user); err != nil {
+			return err
+		}
+		if user.EmployeeID == nil {
+			return nil
+		}
 		var employee models.Employee
-		if err := h.DB.First(&employee, "id = ?", user.EmployeeID).Error; err == nil {
-			employee.Phone = phone
-			employee.Position = position
-			_ = h.DB.Save(&employee).Error
+		if err := tx.First(&employee, "id = ?", user.EmployeeID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
 		}
+		employeeBefore := employee
+		employee.Phone = phone
+		employee.Position = position
+		if err := tx.Save(&employee).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionUpdate, "employee", employee.ID, employeeBefore, employee)
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed"})
+		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -489,7 +524,12 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 	}
 
 	user.PasswordHash = newHash
-	if err := h.DB.Save(&user).Error; err != nil {
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&user).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionUpdate, "user", user.ID, user, auditPasswordChanged(user))
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed"})
 		return
 	}
@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"erp-backend/internal/middleware"
+	"erp-backend/internal/models"
+)
+
+type CreditNoteHandler struct {
+	DB *gorm.DB
+}
+
+type createCreditNoteRequest struct {
+	Amount   float64 `json:"amount" binding:"required"`
+	Reason   string  `json:"reason" binding:"required"`
+	IssuedAt string  `json:"issuedAt"`
+}
+
+func NewCreditNoteHandler(db *gorm.DB) *CreditNoteHandler {
+	return &CreditNoteHandler{DB: db}
+}
+
+func creditNoteNumberExists(db *gorm.DB, number string) (bool, error) {
+	var count int64
+	if err := db.Model(&models.CreditNote{}).Where("number = ?", number).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (h *CreditNoteHandler) List(c *gin.Context) {
+	query := h.DB.Model(&models.CreditNote{})
+	if invoiceID := c.Param("id"); invoiceID != "" {
+		id, err := uuid.Parse(invoiceID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+			return
+		}
+		query = query.Where("invoice_id = ?", id)
+	}
+
+	var notes []models.CreditNote
+	if err := query.Order("issued_at desc, created_at desc").Find(&notes).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load credit notes"})
+		return
+	}
+	c.JSON(http.StatusOK, notes)
+}
+
+// Create issues a credit note against an invoice. A credit note can only
+// reduce what is still owed; refunding money already received is out of scope.
+func (h *CreditNoteHandler) Create(c *gin.Context) {
+	invoiceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req createCreditNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "reason required"})
+		return
+	}
+	if req.Amount <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "amount must be positive"})
+		return
+	}
+
+	issuedAt := time.Now()
+	if req.IssuedAt != "" {
+		parsed, err := time.Parse("2006-01-02", req.IssuedAt)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid issuedAt"})
+			return
+		}
+		issuedAt = parsed
+	}
+
+	note := models.CreditNote{
+		InvoiceID: invoiceID,
+		Amount:    roundMoney(req.Amount),
+		Reason:    reason,
+		IssuedAt:  issuedAt,
+	}
+	if userID, ok := c.Get(middleware.ContextUserID); ok {
+		if parsed, err := uuid.Parse(userID.(string)); err == nil {
+			note.CreatedBy = &parsed
+		}
+	}
+
+	var invoice models.Invoice
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&invoice, "id = ?", invoiceID).Error; err != nil {
+			return err
+		}
+		if invoice.Status == invoiceStatusDraft || invoice.Status == invoiceStatusVoid {
+			return gorm.ErrInvalidTransaction
+		}
+		if note.Amount > invoiceBalance(invoice)+0.005 {
+			return gorm.ErrInvalidData
+		}
+		number, err := allocateSequenceNumber(tx, creditNoteSequenceKey, issuedAt, func(candidate string) (bool, error) {
+			return creditNoteNumberExists(tx, candidate)
+		})
+		if err != nil {
+			return err
+		}
+		note.Number = number
+		if err := tx.Create(&note).Error; err != nil {
+			return err
+		}
+		if err := recordAudit(tx, c, auditActionCreate, "credit_note", note.ID, nil, note); err != nil {
+			return err
+		}
+		return refreshInvoiceStatus(tx, &invoice)
+	}); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "invoice not found"})
+			return
+		}
+		if err == gorm.ErrInvalidTransaction {
+			c.JSON(http.StatusConflict, gin.H{"error": "invoice is not issued"})
+			return
+		}
+		if err == gorm.ErrInvalidData {
+			c.JSON(http.StatusConflict, gin.H{"error": "credit exceeds balance"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "credit note failed"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"creditNote": note,
+		"invoice":    invoice,
+	})
+}
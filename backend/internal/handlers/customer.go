@@ -0,0 +1,226 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"erp-backend/internal/middleware"
+	"erp-backend/internal/models"
+)
+
+type CustomerHandler struct {
+	DB *gorm.DB
+}
+
+type createCustomerRequest struct {
+	Name             string `json:"name" binding:"required"`
+	BillingEmail     string `json:"billingEmail" binding:"omitempty,email"`
+	Address          string `json:"address"`
+	TaxID            string `json:"taxId"`
+	PaymentTermsDays *int   `json:"paymentTermsDays"`
+	Currency         string `json:"currency"`
+}
+
+func NewCustomerHandler(db *gorm.DB) *CustomerHandler {
+	return &CustomerHandler{DB: db}
+}
+
+func normalizeCurrency(value string) (string, bool) {
+	currency := strings.ToUpper(strings.TrimSpace(value))
+	if currency == "" {
+		return "USD", true
+	}
+	if len(currency) != 3 {
+		return "", false
+	}
+	for _, r := range currency {
+		if r < 'A' || r > 'Z' {
+			return "", false
+		}
+	}
+	return currency, true
+}
+
+// applyCustomerRequest validates req and copies it onto customer, returning a
+// client-facing error message when the input is rejected.
+func applyCustomerRequest(customer *models.Customer, req createCustomerRequest) string {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return "name required"
+	}
+	currency, ok := normalizeCurrency(req.Currency)
+	if !ok {
+		return "invalid currency"
+	}
+	terms := 30
+	if req.PaymentTermsDays != nil {
+		terms = *req.PaymentTermsDays
+	}
+	if terms < 0 || terms > 365 {
+		return "paymentTermsDays must be between 0 and 365"
+	}
+
+	customer.Name = name
+	customer.BillingEmail = strings.ToLower(strings.TrimSpace(req.BillingEmail))
+	customer.Address = strings.TrimSpace(req.Address)
+	customer.TaxID = strings.TrimSpace(req.TaxID)
+	customer.PaymentTermsDays = terms
+	customer.Currency = currency
+	return ""
+}
+
+func (h *CustomerHandler) List(c *gin.Context) {
+	role, _ := c.Get(middleware.ContextRole)
+	if role != "admin" && role != "manager" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+
+	var customers []models.Customer
+	if err := h.DB.Order("name asc").Find(&customers).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load customers"})
+		return
+	}
+	c.JSON(http.StatusOK, customers)
+}
+
+func (h *CustomerHandler) Get(c *gin.Context) {
+	customerID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var customer models.Customer
+	if err := h.DB.First(&customer, "id = ?", customerID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "customer not found"})
+		return
+	}
+	c.JSON(http.StatusOK, customer)
+}
+
+func (h *CustomerHandler) Create(c *gin.Context) {
+	var req createCustomerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+
+	if strings.TrimSpace(req.Currency) == "" {
+		req.Currency = baseCurrency(h.DB)
+	}
+
+	var customer models.Customer
+	if message := applyCustomerRequest(&customer, req); message != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return
+	}
+
+	var existing models.Customer
+	if err := h.DB.Where("name = ?", customer.Name).First(&existing).Error; err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "customer already exists"})
+		return
+	} else if err != gorm.ErrRecordNotFound {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "create failed"})
+		return
+	}
+
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&customer).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionCreate, "customer", customer.ID, nil, customer)
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "create failed"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, customer)
+}
+
+func (h *CustomerHandler) Update(c *gin.Context) {
+	var req createCustomerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+
+	customerID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var customer models.Customer
+	if err := h.DB.First(&customer, "id = ?", customerID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "customer not found"})
+		return
+	}
+
+	before := customer
+	if message := applyCustomerRequest(&customer, req); message != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return
+	}
+
+	var existing models.Customer
+	if err := h.DB.Where("name = ? AND id <> ?", customer.Name, customerID).First(&existing).Error; err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "customer already exists"})
+		return
+	} else if err != gorm.ErrRecordNotFound {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed"})
+		return
+	}
+
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&customer).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionUpdate, "customer", customer.ID, before, customer)
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, customer)
+}
+
+func (h *CustomerHandler) Delete(c *gin.Context) {
+	customerID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var customer models.Customer
+	if err := h.DB.First(&customer, "id = ?", customerID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "customer not found"})
+		return
+	}
+
+	var invoiceCount int64
+	if err := h.DB.Model(&models.Invoice{}).Where("customer_id = ?", customerID).Count(&invoiceCount).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "delete failed"})
+		return
+	}
+	if invoiceCount > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "customer has invoices"})
+		return
+	}
+
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.Customer{}, "id = ?", customerID).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionDelete, "customer", customer.ID, customer, nil)
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "delete failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
@@ -25,8 +25,7 @@ func (h *DashboardHandler) Get(c *gin.Context) {
 	var invoiceCount int64
 	_ = h.DB.Model(&models.Invoice{}).Count(&invoiceCount).Error
 
-	var revenue float64
-	_ = h.DB.Model(&models.Invoice{}).Where("status = ?", "paid").Select("COALESCE(SUM(amount),0)").Scan(&revenue).Error
+	revenue, credited, missingRates := h.revenue()
 
 	startOfDay := time.Now().Truncate(24 * time.Hour)
 	var todayAttendance int64
@@ -36,7 +35,59 @@ func (h *DashboardHandler) Get(c *gin.Context) {
 		"employees":       employeeCount,
 		"invoices":        invoiceCount,
 		"revenue":         revenue,
+		"credited":        credited,
 		"todayAttendance": todayAttendance,
-		"currency":        "USD",
+		"currency":        baseCurrency(h.DB),
+		"missingRates":    missingRates,
 	})
 }
+
+type revenueRow struct {
+	Amount   float64
+	On       time.Time
+	Currency string
+}
+
+// revenue sums payments received and credit notes issued on invoices that
+// are not void, converted into the base currency at the rate valid on each
+// document date. Amounts are totalled per currency and day in the database
+// so only one row per rate lookup is loaded. Currencies without a usable
+// rate are reported instead of being silently added at face value.
+func (h *DashboardHandler) revenue() (float64, float64, []string) {
+	var payments []revenueRow
+	_ = h.DB.Model(&models.Payment{}).
+		Select("SUM(payments.amount) AS amount, DATE(payments.paid_at) AS `on`, invoices.currency").
+		Joins("JOIN invoices ON invoices.id = payments.invoice_id").
+		Where("invoices.status <> ?", invoiceStatusVoid).
+		Group("invoices.currency, DATE(payments.paid_at)").
+		Scan(&payments).Error
+
+	var credits []revenueRow
+	_ = h.DB.Model(&models.CreditNote{}).
+		Select("SUM(credit_notes.amount) AS amount, DATE(credit_notes.issued_at) AS `on`, invoices.currency").
+		Joins("JOIN invoices ON invoices.id = credit_notes.invoice_id").
+		Where("invoices.status <> ?", invoiceStatusVoid).
+		Group("invoices.currency, DATE(credit_notes.issued_at)").
+		Scan(&credits).Error
+
+	converter := newCurrencyConverter(h.DB)
+	missing := []string{}
+	seen := map[string]bool{}
+	sum := func(rows []revenueRow) float64 {
+		total := 0.0
+		for _, row := range rows {
+			converted, err := converter.Convert(row.Amount, row.Currency, row.On)
+			if err != nil {
+				if !seen[row.Currency] {
+					seen[row.Currency] = true
+					missing = append(missing, row.Currency)
+				}
+				continue
+			}
+			total += converted
+		}
+		return roundMoney(total)
+	}
+
+	return sum(payments), sum(credits), missing
+}
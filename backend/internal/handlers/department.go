@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"erp-backend/internal/models"
+)
+
+type DepartmentHandler struct {
+	DB *gorm.DB
+}
+
+type createDepartmentRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+}
+
+func NewDepartmentHandler(db *gorm.DB) *DepartmentHandler {
+	return &DepartmentHandler{DB: db}
+}
+
+func (h *DepartmentHandler) List(c *gin.Context) {
+	departments := []models.Department{}
+	if err := h.DB.Order("name asc").Find(&departments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load departments"})
+		return
+	}
+	c.JSON(http.StatusOK, departments)
+}
+
+func (h *DepartmentHandler) Create(c *gin.Context) {
+	var req createDepartmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.Name) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+
+	department := models.Department{
+		Name:        strings.TrimSpace(req.Name),
+		Description: strings.TrimSpace(req.Description),
+	}
+
+	var existing models.Department
+	if err := h.DB.Where("name = ?", department.Name).First(&existing).Error; err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "department already exists"})
+		return
+	} else if err != gorm.ErrRecordNotFound {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "create failed"})
+		return
+	}
+
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&department).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionCreate, "department", department.ID, nil, department)
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "create failed"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, department)
+}
+
+func (h *DepartmentHandler) Update(c *gin.Context) {
+	var req createDepartmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.Name) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+
+	departmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var department models.Department
+	if err := h.DB.First(&department, "id = ?", departmentID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "department not found"})
+		return
+	}
+
+	before := department
+	department.Name = strings.TrimSpace(req.Name)
+	department.Description = strings.TrimSpace(req.Description)
+
+	var existing models.Department
+	if err := h.DB.Where("name = ? AND id <> ?", department.Name, departmentID).First(&existing).Error; err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "department already exists"})
+		return
+	} else if err != gorm.ErrRecordNotFound {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed"})
+		return
+	}
+
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&department).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionUpdate, "department", department.ID, before, department)
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, department)
+}
+
+func (h *DepartmentHandler) Delete(c *gin.Context) {
+	departmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var department models.Department
+	if err := h.DB.First(&department, "id = ?", departmentID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "department not found"})
+		return
+	}
+
+	var employeeCount int64
+	if err := h.DB.Unscoped().Model(&models.Employee{}).Where("department_id = ?", departmentID).Count(&employeeCount).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "delete failed"})
+		return
+	}
+	if employeeCount > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "department has employees"})
+		return
+	}
+
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.Department{}, "id = ?", departmentID).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionDelete, "department", department.ID, department, nil)
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "delete failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
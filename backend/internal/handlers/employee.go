@@ -27,6 +27,10 @@ type createEmployeeRequest struct {
 	Position  string  `json:"position"`
 	Salary    float64 `json:"salary"`
 	HiredAt   string  `json:"hiredAt" binding:"required"`
+	// DepartmentID and ManagerID are left unchanged when omitted and
+	// cleared when sent as an empty string.
+	DepartmentID *string `json:"departmentId"`
+	ManagerID    *string `json:"managerId"`
 }
 
 type createEmployeeUserRequest struct {
@@ -54,6 +58,49 @@ func normalizeEmployeeRole(value string) (string, bool) {
 	return "", false
 }
 
+var employeeSortColumns = map[string]string{
+	"createdAt": "created_at",
+	"firstName": "first_name",
+	"lastName":  "last_name",
+	"email":     "email",
+	"position":  "position",
+	"hiredAt":   "hired_at",
+}
+
+// applyEmployeeOrg validates and applies the department and manager in req.
+func applyEmployeeOrg(db *gorm.DB, employee *models.Employee, req createEmployeeRequest) string {
+	if req.DepartmentID != nil {
+		if *req.DepartmentID == "" {
+			employee.DepartmentID = nil
+		} else {
+			departmentID, err := uuid.Parse(*req.DepartmentID)
+			if err != nil {
+				return "invalid departmentId"
+			}
+			var department models.Department
+			if err := db.First(&department, "id = ?", departmentID).Error; err != nil {
+				return "department not found"
+			}
+			employee.DepartmentID = &departmentID
+		}
+	}
+	if req.ManagerID != nil {
+		if *req.ManagerID == "" {
+			employee.ManagerID = nil
+		} else {
+			managerID, err := uuid.Parse(*req.ManagerID)
+			if err != nil {
+				return "invalid managerId"
+			}
+			if message := validateManager(db, employee.ID, managerID); message != "" {
+				return message
+			}
+			employee.ManagerID = &managerID
+		}
+	}
+	return ""
+}
+
 func (h *EmployeeHandler) List(c *gin.Context) {
 	role, _ := c.Get(middleware.ContextRole)
 	if role == "employee" {
@@ -72,21 +119,94 @@ func (h *EmployeeHandler) List(c *gin.Context) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
 			return
 		}
-		c.JSON(http.StatusOK, []models.Employee{employee})
+		c.JSON(http.StatusOK, pageResponse([]models.Employee{employee}, 1, listParams{Page: 1, Limit: 1}))
 		return
 	}
 
-	query := h.DB.Order("created_at desc")
-	if role == "manager" {
-		query = query.Where("role = ?", "employee")
+	params, message := parseListParams(c, employeeSortColumns, "createdAt")
+	if message != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return
+	}
+	query, status, message := h.listQuery(c)
+	if message != "" {
+		c.JSON(status, gin.H{"error": message})
+		return
+	}
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load employees"})
+		return
 	}
 
-	var employees []models.Employee
-	if err := query.Find(&employees).Error; err != nil {
+	employees := []models.Employee{}
+	if err := params.apply(query, "employees").Find(&employees).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load employees"})
 		return
 	}
-	c.JSON(http.StatusOK, employees)
+	c.JSON(http.StatusOK, pageResponse(employees, total, params))
+}
+
+// listQuery builds the employee query for the filters shared by List and
+// Export. Callers handle the employee role, which only sees itself.
+func (h *EmployeeHandler) listQuery(c *gin.Context) (*gorm.DB, int, string) {
+	role, _ := c.Get(middleware.ContextRole)
+
+	from, to, message := parseDateRange(c)
+	if message != "" {
+		return nil, http.StatusBadRequest, message
+	}
+
+	query := h.DB.Model(&models.Employee{})
+	if c.Query("archived") == "true" {
+		if role != "admin" {
+			return nil, http.StatusForbidden, "forbidden"
+		}
+		query = query.Unscoped().Where("employees.deleted_at IS NOT NULL")
+	}
+	reports, scoped, err := managerScope(h.DB, c)
+	if err != nil {
+		return nil, http.StatusInternalServerError, "could not load employees"
+	}
+	if scoped {
+		query = query.Where("employees.id IN ?", reports)
+	} else if role == "manager" {
+		query = query.Where("employees.role = ?", "employee")
+	}
+	if value := c.Query("role"); value != "" {
+		query = query.Where("employees.role = ?", strings.ToLower(value))
+	}
+	if value := c.Query("departmentId"); value != "" {
+		departmentID, err := uuid.Parse(value)
+		if err != nil {
+			return nil, http.StatusBadRequest, "invalid departmentId"
+		}
+		query = query.Where("employees.department_id = ?", departmentID)
+	}
+	if value := c.Query("managerId"); value != "" {
+		managerID, err := uuid.Parse(value)
+		if err != nil {
+			return nil, http.StatusBadRequest, "invalid managerId"
+		}
+		query = query.Where("employees.manager_id = ?", managerID)
+	}
+	if value := c.Query("status"); value != "" {
+		query = query.Where("employees.status IN ?", strings.Split(value, ","))
+	}
+	if !from.IsZero() {
+		query = query.Where("employees.hired_at >= ?", from)
+	}
+	if !to.IsZero() {
+		query = query.Where("employees.hired_at < ?", to)
+	}
+	if value := strings.TrimSpace(c.Query("q")); value != "" {
+		like := containsPattern(value)
+		query = query.Where("employees.first_name LIKE ? OR employees.last_name LIKE ? OR employees.email LIKE ? OR employees.position LIKE ?",
+			like, like, like, like)
+	}
+	return query, 0, ""
 }
 
 func (h *EmployeeHandler) Create(c *gin.Context) {
@@ -98,30 +218,56 @@ func (h *EmployeeHandler) Create(c *gin.Context) {
 		return
 	}
 
-	normalizedEmail := strings.ToLower(strings.TrimSpace(req.Email))
-	var existing models.Employee
-	if err := h.DB.Where("email = ?", normalizedEmail).First(&existing).Error; err == nil {
-		c.JSON(http.StatusConflict, gin.H{"error": "email already exists"})
+	employee, status, message := prepareEmployee(h.DB, actorRole, req)
+	if message == "" {
+		status, message = scopeEmployeeManager(h.DB, c, &employee)
+	}
+	if message != "" {
+		c.JSON(status, gin.H{"error": message})
 		return
-	} else if err != gorm.ErrRecordNotFound {
+	}
+
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&employee).Error; err != nil {
+			return err
+		}
+		if err := recordStartingSalary(tx, c, &employee); err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionCreate, "employee", employee.ID, nil, employee)
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "create failed"})
 		return
 	}
 
+	c.JSON(http.StatusCreated, employee)
+}
+
+// prepareEmployee validates req as a new employee created by actorRole. On
+// failure it returns the HTTP status and message to report.
+func prepareEmployee(db *gorm.DB, actorRole any, req createEmployeeRequest) (models.Employee, int, string) {
+	normalizedEmail := strings.ToLower(strings.TrimSpace(req.Email))
+	var existing models.Employee
+	if err := db.Unscoped().Where("email = ?", normalizedEmail).First(&existing).Error; err == nil {
+		if existing.DeletedAt.Valid {
+			return models.Employee{}, http.StatusConflict, "email belongs to an archived employee"
+		}
+		return models.Employee{}, http.StatusConflict, "email already exists"
+	} else if err != gorm.ErrRecordNotFound {
+		return models.Employee{}, http.StatusInternalServerError, "create failed"
+	}
+
 	hiredAt, err := time.Parse("2006-01-02", req.HiredAt)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hiredAt"})
-		return
+		return models.Employee{}, http.StatusBadRequest, "invalid hiredAt"
 	}
 
 	role, validRole := normalizeEmployeeRole(req.Role)
 	if !validRole {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid role"})
-		return
+		return models.Employee{}, http.StatusBadRequest, "invalid role"
 	}
 	if actorRole == "manager" && role == "manager" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "only admin can add manager"})
-		return
+		return models.Employee{}, http.StatusForbidden, "only admin can add manager"
 	}
 
 	employee := models.Employee{
@@ -131,16 +277,15 @@ func (h *EmployeeHandler) Create(c *gin.Context) {
 		Role:      role,
 		Phone:     req.Phone,
 		Position:  req.Position,
-		Salary:    req.Salary,
+		Salary:    roundMoney(req.Salary),
 		HiredAt:   hiredAt,
+		Status:    employeeStatusActive,
 	}
-
-	if err := h.DB.Create(&employee).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "create failed"})
-		return
+	employee.SalaryCurrency = baseCurrency(db)
+	if message := applyEmployeeOrg(db, &employee, req); message != "" {
+		return models.Employee{}, http.StatusBadRequest, message
 	}
-
-	c.JSON(http.StatusCreated, employee)
+	return employee, 0, ""
 }
 
 func (h *EmployeeHandler) Update(c *gin.Context) {
@@ -152,12 +297,6 @@ func (h *EmployeeHandler) Update(c *gin.Context) {
 		return
 	}
 
-	employeeID, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
-		return
-	}
-
 	hiredAt, err := time.Parse("2006-01-02", req.HiredAt)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid hiredAt"})
@@ -170,15 +309,12 @@ func (h *EmployeeHandler) Update(c *gin.Context) {
 		return
 	}
 
-	var employee models.Employee
-	if err := h.DB.First(&employee, "id = ?", employeeID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
-		return
-	}
-	if actorRole == "manager" && strings.EqualFold(employee.Role, "manager") {
-		c.JSON(http.StatusForbidden, gin.H{"error": "manager cannot manage manager"})
+	employee, status, message := accessibleEmployee(h.DB, c, true)
+	if message != "" {
+		c.JSON(status, gin.H{"error": message})
 		return
 	}
+	employeeID := employee.ID
 	if actorRole == "manager" && role == "manager" {
 		c.JSON(http.StatusForbidden, gin.H{"error": "only admin can update manager role"})
 		return
@@ -186,7 +322,7 @@ func (h *EmployeeHandler) Update(c *gin.Context) {
 
 	normalizedEmail := strings.ToLower(strings.TrimSpace(req.Email))
 	var existing models.Employee
-	if err := h.DB.Where("email = ? AND id <> ?", normalizedEmail, employeeID).First(&existing).Error; err == nil {
+	if err := h.DB.Unscoped().Where("email = ? AND id <> ?", normalizedEmail, employeeID).First(&existing).Error; err == nil {
 		c.JSON(http.StatusConflict, gin.H{"error": "email already exists"})
 		return
 	} else if err != gorm.ErrRecordNotFound {
@@ -194,16 +330,51 @@ func (h *EmployeeHandler) Update(c *gin.Context) {
 		return
 	}
 
+	before := employee
 	employee.FirstName = req.FirstName
 	employee.LastName = req.LastName
 	employee.Email = normalizedEmail
 	employee.Role = role
 	employee.Phone = req.Phone
 	employee.Position = req.Position
-	employee.Salary = req.Salary
 	employee.HiredAt = hiredAt
+	if message := applyEmployeeOrg(h.DB, &employee, req); message != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return
+	}
+	if status, message := scopeEmployeeManager(h.DB, c, &employee); message != "" {
+		c.JSON(status, gin.H{"error": message})
+		return
+	}
 
-	if err := h.DB.Save(&employee).Error; err != nil {
+	// A different salary is recorded as a compensation change effective
+	// today rather than overwriting the previous value.
+	salary := roundMoney(req.Salary)
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&employee).Error; err != nil {
+			return err
+		}
+		if salary != employee.Salary {
+			change := models.CompensationChange{
+				EmployeeID:    employee.ID,
+				EffectiveDate: utcToday(),
+				Amount:        salary,
+				Currency:      employee.SalaryCurrency,
+				Reason:        "Salary updated",
+				ApprovedBy:    actorUserID(c),
+			}
+			if change.Currency == "" {
+				change.Currency = baseCurrency(tx)
+			}
+			if err := saveCompensationChange(tx, c, &change); err != nil {
+				return err
+			}
+			if err := tx.First(&employee, "id = ?", employee.ID).Error; err != nil {
+				return err
+			}
+		}
+		return recordAudit(tx, c, auditActionUpdate, "employee", employee.ID, before, employee)
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed"})
 		return
 	}
@@ -220,40 +391,89 @@ func (h *EmployeeHandler) Update(c *gin.Context) {
 }
 
 func (h *EmployeeHandler) Delete(c *gin.Context) {
-	actorRole, _ := c.Get(middleware.ContextRole)
+	employee, status, message := accessibleEmployee(h.DB, c, true)
+	if message != "" {
+		c.JSON(status, gin.H{"error": message})
+		return
+	}
+	employeeID := employee.ID
+
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.Employee{}, "id = ?", employeeID).Error; err != nil {
+			return err
+		}
+		if err := disableEmployeeLogin(tx, employeeID, time.Now()); err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionDelete, "employee", employee.ID, employee, nil)
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "delete failed"})
+		return
+	}
 
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
+
+// Restore brings back an archived employee and re-enables the linked login.
+func (h *EmployeeHandler) Restore(c *gin.Context) {
 	employeeID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
 		return
 	}
 
-	if actorRole == "manager" {
-		var employee models.Employee
-		if err := h.DB.First(&employee, "id = ?", employeeID).Error; err == nil {
-			if strings.EqualFold(employee.Role, "manager") {
-				c.JSON(http.StatusForbidden, gin.H{"error": "manager cannot manage manager"})
-				return
+	var employee models.Employee
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("deleted_at IS NOT NULL").First(&employee, "id = ?", employeeID).Error; err != nil {
+			return err
+		}
+		before := employee
+		if err := tx.Unscoped().Model(&employee).Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		employee.DeletedAt = gorm.DeletedAt{}
+		if employee.Status != employeeStatusTerminated {
+			if err := enableEmployeeLogin(tx, employeeID); err != nil {
+				return err
 			}
 		}
+		return recordAudit(tx, c, auditActionRestore, "employee", employee.ID, before, employee)
+	}); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "archived employee not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "restore failed"})
+		return
 	}
 
-	var employee models.Employee
-	if err := h.DB.First(&employee, "id = ?", employeeID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
-		return
+	c.JSON(http.StatusOK, employee)
+}
+
+// disableEmployeeLogin blocks the user linked to an employee from logging in
+// and revokes the refresh tokens already issued to it.
+func disableEmployeeLogin(tx *gorm.DB, employeeID uuid.UUID, at time.Time) error {
+	var userIDs []uuid.UUID
+	if err := tx.Model(&models.User{}).Where("employee_id = ?", employeeID).Pluck("id", &userIDs).Error; err != nil {
+		return err
 	}
-	if actorRole == "manager" && strings.EqualFold(employee.Role, "manager") {
-		c.JSON(http.StatusForbidden, gin.H{"error": "manager cannot manage manager"})
-		return
+	if len(userIDs) == 0 {
+		return nil
 	}
-
-	if err := h.DB.Delete(&models.Employee{}, "id = ?", employeeID).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "delete failed"})
-		return
+	if err := tx.Model(&models.User{}).
+		Where("id IN ? AND disabled_at IS NULL", userIDs).
+		Update("disabled_at", at).Error; err != nil {
+		return err
 	}
+	return tx.Model(&models.RefreshToken{}).
+		Where("user_id IN ? AND revoked_at IS NULL", userIDs).
+		Update("revoked_at", at).Error
+}
 
-	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+func enableEmployeeLogin(tx *gorm.DB, employeeID uuid.UUID) error {
+	return tx.Model(&models.User{}).
+		Where("employee_id = ?", employeeID).
+		Update("disabled_at", nil).Error
 }
 
 func (h *EmployeeHandler) CreateUser(c *gin.Context) {
@@ -269,19 +489,9 @@ func (h *EmployeeHandler) CreateUser(c *gin.Context) {
 		return
 	}
 
-	employeeID, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
-		return
-	}
-
-	var employee models.Employee
-	if err := h.DB.First(&employee, "id = ?", employeeID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
-		return
-	}
-	if role == "manager" && strings.EqualFold(employee.Role, "manager") {
-		c.JSON(http.StatusForbidden, gin.H{"error": "manager cannot manage manager"})
+	employee, status, message := accessibleEmployee(h.DB, c, true)
+	if message != "" {
+		c.JSON(status, gin.H{"error": message})
 		return
 	}
 
@@ -316,7 +526,12 @@ func (h *EmployeeHandler) CreateUser(c *gin.Context) {
 		EmployeeID:   &employee.ID,
 	}
 
-	if err := h.DB.Create(&user).Error; err != nil {
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionCreate, "user", user.ID, nil, user)
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "user creation failed"})
 		return
 	}
@@ -343,21 +558,12 @@ func (h *EmployeeHandler) UpsertUserPassword(c *gin.Context) {
 		return
 	}
 
-	employeeID, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
-		return
-	}
-
-	var employee models.Employee
-	if err := h.DB.First(&employee, "id = ?", employeeID).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
-		return
-	}
-	if role == "manager" && strings.EqualFold(employee.Role, "manager") {
-		c.JSON(http.StatusForbidden, gin.H{"error": "manager cannot manage manager"})
+	employee, status, message := accessibleEmployee(h.DB, c, true)
+	if message != "" {
+		c.JSON(status, gin.H{"error": message})
 		return
 	}
+	employeeID := employee.ID
 
 	normalizedEmail := strings.ToLower(strings.TrimSpace(employee.Email))
 
@@ -368,11 +574,17 @@ func (h *EmployeeHandler) UpsertUserPassword(c *gin.Context) {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "password error"})
 			return
 		}
+		before := user
 		user.PasswordHash = passwordHash
 		user.Role = employee.Role
 		user.Email = normalizedEmail
 		user.Name = employee.FirstName + " " + employee.LastName
-		if err := h.DB.Save(&user).Error; err != nil {
+		if err := h.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Save(&user).Error; err != nil {
+				return err
+			}
+			return recordAudit(tx, c, auditActionUpdate, "user", user.ID, before, auditPasswordChanged(user))
+		}); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed"})
 			return
 		}
@@ -406,7 +618,12 @@ func (h *EmployeeHandler) UpsertUserPassword(c *gin.Context) {
 		EmployeeID:   &employee.ID,
 	}
 
-	if err := h.DB.Create(&user).Error; err != nil {
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionCreate, "user", user.ID, nil, user)
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "user creation failed"})
 		return
 	}
@@ -0,0 +1,260 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"erp-backend/internal/middleware"
+	"erp-backend/internal/models"
+)
+
+type createCompensationChangeRequest struct {
+	EffectiveDate string  `json:"effectiveDate" binding:"required"`
+	Amount        float64 `json:"amount"`
+	Currency      string  `json:"currency"`
+	Reason        string  `json:"reason" binding:"required"`
+}
+
+func actorUserID(c *gin.Context) *uuid.UUID {
+	if c == nil {
+		return nil
+	}
+	value, ok := c.Get(middleware.ContextUserID)
+	if !ok {
+		return nil
+	}
+	id, err := uuid.Parse(fmt.Sprint(value))
+	if err != nil {
+		return nil
+	}
+	return &id
+}
+
+// saveCompensationChange records change and re-derives the employee's
+// current salary. A change dated like an earlier one is kept alongside it so
+// the history shows both; the later one takes effect.
+func saveCompensationChange(tx *gorm.DB, c *gin.Context, change *models.CompensationChange) error {
+	if err := tx.Create(change).Error; err != nil {
+		return err
+	}
+	if err := recordAudit(tx, c, auditActionCreate, "compensation_change", change.ID, nil, *change); err != nil {
+		return err
+	}
+	return syncEmployeeSalary(tx, change.EmployeeID, time.Now())
+}
+
+// syncEmployeeSalary sets the employee's salary to the latest change in
+// effect at now and marks the changes in effect as applied. Employees
+// without an effective change keep their stored salary.
+func syncEmployeeSalary(tx *gorm.DB, employeeID uuid.UUID, now time.Time) error {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	var current models.CompensationChange
+	err := tx.Where("employee_id = ? AND effective_date <= ?", employeeID, today).
+		Order("effective_date desc, created_at desc").First(&current).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := tx.Unscoped().Model(&models.Employee{}).
+		Where("id = ?", employeeID).
+		Updates(map[string]any{"salary": current.Amount, "salary_currency": current.Currency}).Error; err != nil {
+		return err
+	}
+	return tx.Model(&models.CompensationChange{}).
+		Where("employee_id = ? AND effective_date <= ? AND applied_at IS NULL", employeeID, today).
+		Update("applied_at", now).Error
+}
+
+// recordStartingSalary opens the compensation history of a new employee
+// with the salary they were created with.
+func recordStartingSalary(tx *gorm.DB, c *gin.Context, employee *models.Employee) error {
+	if employee.Salary <= 0 {
+		return nil
+	}
+	change := models.CompensationChange{
+		EmployeeID:    employee.ID,
+		EffectiveDate: employee.HiredAt,
+		Amount:        employee.Salary,
+		Currency:      employee.SalaryCurrency,
+		Reason:        "Starting salary",
+		ApprovedBy:    actorUserID(c),
+	}
+	return saveCompensationChange(tx, c, &change)
+}
+
+// ListCompensation returns the salary in effect, the past changes and the
+// changes scheduled for a later date.
+func (h *EmployeeHandler) ListCompensation(c *gin.Context) {
+	employee, status, message := accessibleEmployee(h.DB, c, false)
+	if message != "" {
+		c.JSON(status, gin.H{"error": message})
+		return
+	}
+
+	var changes []models.CompensationChange
+	if err := h.DB.Where("employee_id = ?", employee.ID).
+		Order("effective_date desc, created_at desc").Find(&changes).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load compensation"})
+		return
+	}
+
+	today := utcToday()
+	history := []models.CompensationChange{}
+	scheduled := []models.CompensationChange{}
+	for _, change := range changes {
+		if change.EffectiveDate.After(today) {
+			scheduled = append(scheduled, change)
+		} else {
+			history = append(history, change)
+		}
+	}
+	var current *models.CompensationChange
+	if len(history) > 0 {
+		current = &history[0]
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"current":   current,
+		"history":   history,
+		"scheduled": scheduled,
+	})
+}
+
+// CreateCompensation records a salary change. Changes dated today or earlier
+// update the current salary straight away; later ones are scheduled and
+// applied by RunCompensationChanges on their effective date.
+func (h *EmployeeHandler) CreateCompensation(c *gin.Context) {
+	employee, status, message := accessibleEmployee(h.DB, c, true)
+	if message != "" {
+		c.JSON(status, gin.H{"error": message})
+		return
+	}
+
+	var req createCompensationChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.Reason) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "effectiveDate, amount and reason required"})
+		return
+	}
+	effectiveDate, err := time.Parse("2006-01-02", req.EffectiveDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid effectiveDate"})
+		return
+	}
+	if req.Amount < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "amount cannot be negative"})
+		return
+	}
+	currency := employee.SalaryCurrency
+	if req.Currency != "" || currency == "" {
+		normalized, ok := normalizeCurrency(req.Currency)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid currency"})
+			return
+		}
+		currency = normalized
+	}
+	if employee.Status == employeeStatusTerminated {
+		c.JSON(http.StatusConflict, gin.H{"error": "employee is terminated"})
+		return
+	}
+
+	change := models.CompensationChange{
+		EmployeeID:    employee.ID,
+		EffectiveDate: effectiveDate,
+		Amount:        roundMoney(req.Amount),
+		Currency:      currency,
+		Reason:        strings.TrimSpace(req.Reason),
+		ApprovedBy:    actorUserID(c),
+	}
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		return saveCompensationChange(tx, c, &change)
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "create failed"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, change)
+}
+
+// DeleteCompensation cancels a scheduled change. Changes already in effect
+// are part of the history and cannot be removed.
+func (h *EmployeeHandler) DeleteCompensation(c *gin.Context) {
+	employee, status, message := accessibleEmployee(h.DB, c, true)
+	if message != "" {
+		c.JSON(status, gin.H{"error": message})
+		return
+	}
+	changeID, err := uuid.Parse(c.Param("changeId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid changeId"})
+		return
+	}
+
+	var change models.CompensationChange
+	if err := h.DB.First(&change, "id = ? AND employee_id = ?", changeID, employee.ID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "compensation change not found"})
+		return
+	}
+	if !change.EffectiveDate.After(utcToday()) {
+		c.JSON(http.StatusConflict, gin.H{"error": "only scheduled changes can be cancelled"})
+		return
+	}
+
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.CompensationChange{}, "id = ?", change.ID).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionDelete, "compensation_change", change.ID, change, nil)
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "delete failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
+
+// RunCompensationChanges applies scheduled changes whose effective date has
+// been reached.
+func (h *EmployeeHandler) RunCompensationChanges(now time.Time) error {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	var due []uuid.UUID
+	if err := h.DB.Model(&models.CompensationChange{}).
+		Where("applied_at IS NULL AND effective_date <= ?", today).
+		Distinct("employee_id").
+		Pluck("employee_id", &due).Error; err != nil {
+		return err
+	}
+
+	for _, employeeID := range due {
+		if err := h.DB.Transaction(func(tx *gorm.DB) error {
+			var before models.Employee
+			if err := tx.Unscoped().First(&before, "id = ?", employeeID).Error; err != nil {
+				return err
+			}
+			if err := syncEmployeeSalary(tx, employeeID, now); err != nil {
+				return err
+			}
+			var after models.Employee
+			if err := tx.Unscoped().First(&after, "id = ?", employeeID).Error; err != nil {
+				return err
+			}
+			if after.Salary == before.Salary && after.SalaryCurrency == before.SalaryCurrency {
+				return nil
+			}
+			return recordAudit(tx, nil, auditActionUpdate, "employee", employeeID, before, after)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
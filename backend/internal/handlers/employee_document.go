@@ -0,0 +1,269 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"erp-backend/internal/middleware"
+	"erp-backend/internal/models"
+	"erp-backend/internal/storage"
+)
+
+type EmployeeDocumentHandler struct {
+	DB      *gorm.DB
+	Storage storage.Storage
+}
+
+const maxEmployeeDocumentSize = 10 << 20
+
+var employeeDocumentTypes = map[string]bool{
+	"contract":    true,
+	"id":          true,
+	"certificate": true,
+	"other":       true,
+}
+
+// employeeDocumentContentTypes maps sniffed content types to the type stored
+// with the document. Zip archives are only accepted as office documents,
+// recognised by their extension.
+var employeeDocumentContentTypes = map[string]string{
+	"application/pdf": "application/pdf",
+	"image/png":       "image/png",
+	"image/jpeg":      "image/jpeg",
+	"image/gif":       "image/gif",
+	"image/webp":      "image/webp",
+	"text/plain":      "text/plain; charset=utf-8",
+}
+
+var officeDocumentContentTypes = map[string]string{
+	".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	".xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	".pptx": "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+}
+
+func NewEmployeeDocumentHandler(db *gorm.DB, store storage.Storage) *EmployeeDocumentHandler {
+	return &EmployeeDocumentHandler{DB: db, Storage: store}
+}
+
+// documentContentType sniffs data and returns the content type to store, or
+// false when the file type is not accepted.
+func documentContentType(data []byte, fileName string) (string, bool) {
+	sniffed := http.DetectContentType(data)
+	if index := strings.Index(sniffed, ";"); index >= 0 {
+		sniffed = sniffed[:index]
+	}
+	if sniffed == "application/zip" {
+		contentType, ok := officeDocumentContentTypes[strings.ToLower(filepath.Ext(fileName))]
+		return contentType, ok
+	}
+	contentType, ok := employeeDocumentContentTypes[sniffed]
+	return contentType, ok
+}
+
+func documentFileName(value string) string {
+	name := filepath.Base(strings.ReplaceAll(value, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == '"' || r == 0x7f {
+			return -1
+		}
+		return r
+	}, name)
+	if name == "." || name == "/" || name == "" {
+		name = "document"
+	}
+	if len(name) > 255 {
+		name = name[len(name)-255:]
+	}
+	return name
+}
+
+func (h *EmployeeDocumentHandler) List(c *gin.Context) {
+	employee, status, message := accessibleEmployee(h.DB, c, false)
+	if message != "" {
+		c.JSON(status, gin.H{"error": message})
+		return
+	}
+
+	query := h.DB.Where("employee_id = ?", employee.ID)
+	if value := c.Query("type"); value != "" {
+		query = query.Where("type = ?", value)
+	}
+	documents := []models.EmployeeDocument{}
+	if err := query.Order("created_at desc").Find(&documents).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load documents"})
+		return
+	}
+	c.JSON(http.StatusOK, documents)
+}
+
+// Upload stores a multipart file with its document type and optional expiry
+// date. The content type is sniffed from the file rather than trusted from
+// the client.
+func (h *EmployeeDocumentHandler) Upload(c *gin.Context) {
+	employee, status, message := accessibleEmployee(h.DB, c, true)
+	if message != "" {
+		c.JSON(status, gin.H{"error": message})
+		return
+	}
+
+	documentType := strings.ToLower(strings.TrimSpace(c.PostForm("type")))
+	if !employeeDocumentTypes[documentType] {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "type must be contract, id, certificate or other"})
+		return
+	}
+	var expiresAt *time.Time
+	if value := strings.TrimSpace(c.PostForm("expiresAt")); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid expiresAt"})
+			return
+		}
+		expiresAt = &parsed
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	if header.Size > maxEmployeeDocumentSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file is too large"})
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid file"})
+		return
+	}
+	data, err := io.ReadAll(io.LimitReader(file, maxEmployeeDocumentSize+1))
+	file.Close()
+	if err != nil || len(data) > maxEmployeeDocumentSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid file"})
+		return
+	}
+	if len(data) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is empty"})
+		return
+	}
+
+	fileName := documentFileName(header.Filename)
+	contentType, ok := documentContentType(data, fileName)
+	if !ok {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "file type not allowed"})
+		return
+	}
+
+	document := models.EmployeeDocument{
+		ID:          uuid.New(),
+		EmployeeID:  employee.ID,
+		Type:        documentType,
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		ExpiresAt:   expiresAt,
+	}
+	document.StorageKey = fmt.Sprintf("employees/%s/documents/%s%s", employee.ID, document.ID, strings.ToLower(filepath.Ext(fileName)))
+	if value, ok := c.Get(middleware.ContextUserID); ok {
+		if id, err := uuid.Parse(fmt.Sprint(value)); err == nil {
+			document.UploadedBy = &id
+		}
+	}
+
+	if err := h.Storage.Put(c.Request.Context(), document.StorageKey, data, contentType); err != nil {
+		log.Printf("document upload %s: %v", document.StorageKey, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "upload failed"})
+		return
+	}
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&document).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionCreate, "employee_document", document.ID, nil, document)
+	}); err != nil {
+		_ = h.Storage.Delete(c.Request.Context(), document.StorageKey)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "upload failed"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, document)
+}
+
+func (h *EmployeeDocumentHandler) Download(c *gin.Context) {
+	employee, status, message := accessibleEmployee(h.DB, c, false)
+	if message != "" {
+		c.JSON(status, gin.H{"error": message})
+		return
+	}
+	documentID, err := uuid.Parse(c.Param("documentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid documentId"})
+		return
+	}
+
+	var document models.EmployeeDocument
+	if err := h.DB.First(&document, "id = ? AND employee_id = ?", documentID, employee.ID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "document not found"})
+		return
+	}
+
+	reader, err := h.Storage.Get(c.Request.Context(), document.StorageKey)
+	if err != nil {
+		if err == storage.ErrNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "document file missing"})
+			return
+		}
+		log.Printf("document download %s: %v", document.StorageKey, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "download failed"})
+		return
+	}
+	defer reader.Close()
+
+	c.DataFromReader(http.StatusOK, document.Size, document.ContentType, reader, map[string]string{
+		"Content-Disposition":    `attachment; filename="` + document.FileName + `"`,
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+func (h *EmployeeDocumentHandler) Delete(c *gin.Context) {
+	employee, status, message := accessibleEmployee(h.DB, c, true)
+	if message != "" {
+		c.JSON(status, gin.H{"error": message})
+		return
+	}
+	documentID, err := uuid.Parse(c.Param("documentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid documentId"})
+		return
+	}
+
+	var document models.EmployeeDocument
+	if err := h.DB.First(&document, "id = ? AND employee_id = ?", documentID, employee.ID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "document not found"})
+		return
+	}
+
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.EmployeeDocument{}, "id = ?", document.ID).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionDelete, "employee_document", document.ID, document, nil)
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "delete failed"})
+		return
+	}
+	// The row is gone either way; a leftover file is only logged.
+	if err := h.Storage.Delete(c.Request.Context(), document.StorageKey); err != nil && err != storage.ErrNotFound {
+		log.Printf("document delete %s: %v", document.StorageKey, err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
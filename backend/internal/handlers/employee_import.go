@@ -0,0 +1,447 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"erp-backend/internal/middleware"
+	"erp-backend/internal/models"
+	"erp-backend/internal/xlsx"
+)
+
+const (
+	maxEmployeeImportSize = 5 << 20
+	maxEmployeeImportRows = 1000
+)
+
+// employeeFileColumns lists the columns written by Export. Import accepts
+// the same headers, in any order and case; status is ignored on import.
+var employeeFileColumns = []string{
+	"firstName", "lastName", "email", "role", "phone", "position", "salary",
+	"hiredAt", "department", "managerEmail", "status",
+}
+
+var tooManyImportRows = "too many rows, the limit is " + strconv.Itoa(maxEmployeeImportRows)
+
+var requiredImportColumns = []string{"firstName", "lastName", "email", "hiredAt"}
+
+// errImportRollback aborts the import transaction after a dry run or when
+// any row failed validation.
+var errImportRollback = errors.New("import rolled back")
+
+type employeeImportError struct {
+	Row   int    `json:"row"`
+	Email string `json:"email,omitempty"`
+	Error string `json:"error"`
+}
+
+type employeeImportRow struct {
+	line         int
+	values       map[string]string
+	employee     models.Employee
+	managerEmail string
+}
+
+// csvFormulaPrefixes are the leading characters that make spreadsheet apps
+// evaluate a CSV cell as a formula.
+const csvFormulaPrefixes = "=+-@"
+
+// escapeCSVCell prefixes value with a quote when a spreadsheet would read it
+// as a formula, so exported data is shown as text instead of run.
+func escapeCSVCell(value string) string {
+	if value != "" && strings.ContainsRune(csvFormulaPrefixes, rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// unescapeCSVCell drops the quote added by escapeCSVCell, so an exported
+// file imports unchanged.
+func unescapeCSVCell(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune(csvFormulaPrefixes, rune(value[1])) {
+		return value[1:]
+	}
+	return value
+}
+
+func importColumnKey(header string) string {
+	replacer := strings.NewReplacer(" ", "", "_", "", "-", "", "\ufeff", "")
+	return strings.ToLower(replacer.Replace(strings.TrimSpace(header)))
+}
+
+// readEmployeeFile parses an uploaded CSV or XLSX file into rows keyed by
+// column. XLSX files are detected by their zip signature.
+func readEmployeeFile(data []byte) ([]employeeImportRow, string) {
+	var records [][]string
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		// One extra row for the header.
+		rows, err := xlsx.ReadRows(bytes.NewReader(data), int64(len(data)), maxEmployeeImportRows+1)
+		if errors.Is(err, xlsx.ErrTooManyRows) {
+			return nil, tooManyImportRows
+		}
+		if err != nil {
+			return nil, "invalid xlsx file"
+		}
+		records = rows
+	} else {
+		reader := csv.NewReader(bytes.NewReader(data))
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		rows, err := reader.ReadAll()
+		if err != nil {
+			return nil, "invalid csv file"
+		}
+		records = rows
+	}
+	if len(records) == 0 {
+		return nil, "file is empty"
+	}
+
+	columns := map[string]string{}
+	for _, name := range employeeFileColumns {
+		columns[importColumnKey(name)] = name
+	}
+	header := make([]string, len(records[0]))
+	present := map[string]bool{}
+	for i, value := range records[0] {
+		header[i] = columns[importColumnKey(value)]
+		present[header[i]] = true
+	}
+	for _, name := range requiredImportColumns {
+		if !present[name] {
+			return nil, "missing column " + name
+		}
+	}
+
+	rows := []employeeImportRow{}
+	for i, record := range records[1:] {
+		values := map[string]string{}
+		blank := true
+		for j, value := range record {
+			if j >= len(header) || header[j] == "" {
+				continue
+			}
+			value = unescapeCSVCell(strings.TrimSpace(value))
+			values[header[j]] = value
+			if value != "" {
+				blank = false
+			}
+		}
+		if blank {
+			continue
+		}
+		rows = append(rows, employeeImportRow{line: i + 2, values: values})
+	}
+	if len(rows) > maxEmployeeImportRows {
+		return nil, tooManyImportRows
+	}
+	return rows, ""
+}
+
+// importHiredAt accepts ISO dates and the serial day numbers spreadsheets
+// store for date cells.
+func importHiredAt(value string) string {
+	serial, err := strconv.ParseFloat(value, 64)
+	if err != nil || serial < 1 {
+		return value
+	}
+	return time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC).AddDate(0, 0, int(serial)).Format("2006-01-02")
+}
+
+// Import creates employees from a CSV or XLSX upload. Every row is validated
+// with the rules of Create; nothing is saved unless all rows are valid, and
+// dryRun=true only reports the errors.
+func (h *EmployeeHandler) Import(c *gin.Context) {
+	actorRole, _ := c.Get(middleware.ContextRole)
+	dryRun := c.Query("dryRun") == "true"
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "file is required"})
+		return
+	}
+	if header.Size > maxEmployeeImportSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file is too large"})
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid file"})
+		return
+	}
+	data, err := io.ReadAll(io.LimitReader(file, maxEmployeeImportSize+1))
+	file.Close()
+	if err != nil || len(data) > maxEmployeeImportSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid file"})
+		return
+	}
+
+	rows, message := readEmployeeFile(data)
+	if message != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return
+	}
+
+	var departments []models.Department
+	if err := h.DB.Find(&departments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "import failed"})
+		return
+	}
+	departmentIDs := map[string]string{}
+	for _, department := range departments {
+		departmentIDs[strings.ToLower(department.Name)] = department.ID.String()
+	}
+
+	rowErrors := []employeeImportError{}
+	created := 0
+	err = h.DB.Transaction(func(tx *gorm.DB) error {
+		// Rows are created first and managers assigned afterwards so that
+		// managerEmail may refer to an employee later in the same file.
+		valid := []*employeeImportRow{}
+		for i := range rows {
+			row := &rows[i]
+			values := row.values
+			req := createEmployeeRequest{
+				FirstName: values["firstName"],
+				LastName:  values["lastName"],
+				Email:     values["email"],
+				Role:      values["role"],
+				Phone:     values["phone"],
+				Position:  values["position"],
+				HiredAt:   importHiredAt(values["hiredAt"]),
+			}
+			fail := func(message string) {
+				rowErrors = append(rowErrors, employeeImportError{Row: row.line, Email: values["email"], Error: message})
+			}
+			if err := binding.Validator.ValidateStruct(&req); err != nil {
+				fail("firstName, lastName, hiredAt and a valid email are required")
+				continue
+			}
+			if value := values["salary"]; value != "" {
+				salary, err := strconv.ParseFloat(value, 64)
+				if err != nil || salary < 0 {
+					fail("invalid salary")
+					continue
+				}
+				req.Salary = salary
+			}
+			if value := values["department"]; value != "" {
+				departmentID, ok := departmentIDs[strings.ToLower(value)]
+				if !ok {
+					fail("department not found")
+					continue
+				}
+				req.DepartmentID = &departmentID
+			}
+
+			employee, status, message := prepareEmployee(tx, actorRole, req)
+			if message == "" {
+				status, message = scopeEmployeeManager(tx, c, &employee)
+			}
+			if status == http.StatusInternalServerError {
+				return errors.New(message)
+			}
+			if message != "" {
+				fail(message)
+				continue
+			}
+			if err := tx.Create(&employee).Error; err != nil {
+				return err
+			}
+			if err := recordStartingSalary(tx, c, &employee); err != nil {
+				return err
+			}
+			row.employee = employee
+			row.managerEmail = strings.ToLower(values["managerEmail"])
+			valid = append(valid, row)
+		}
+
+		for _, row := range valid {
+			if row.managerEmail != "" {
+				var manager models.Employee
+				if err := tx.Where("email = ?", row.managerEmail).First(&manager).Error; err != nil {
+					if err != gorm.ErrRecordNotFound {
+						return err
+					}
+					rowErrors = append(rowErrors, employeeImportError{Row: row.line, Email: row.employee.Email, Error: "manager not found"})
+					continue
+				}
+				if message := validateManager(tx, row.employee.ID, manager.ID); message != "" {
+					rowErrors = append(rowErrors, employeeImportError{Row: row.line, Email: row.employee.Email, Error: message})
+					continue
+				}
+				candidate := row.employee
+				candidate.ManagerID = &manager.ID
+				status, message := scopeEmployeeManager(tx, c, &candidate)
+				if status == http.StatusInternalServerError {
+					return errors.New(message)
+				}
+				if message != "" {
+					rowErrors = append(rowErrors, employeeImportError{Row: row.line, Email: row.employee.Email, Error: message})
+					continue
+				}
+				if err := tx.Model(&row.employee).Update("manager_id", manager.ID).Error; err != nil {
+					return err
+				}
+				row.employee.ManagerID = &manager.ID
+			}
+			if err := recordAudit(tx, c, auditActionCreate, "employee", row.employee.ID, nil, row.employee); err != nil {
+				return err
+			}
+		}
+
+		if dryRun || len(rowErrors) > 0 {
+			return errImportRollback
+		}
+		created = len(valid)
+		return nil
+	})
+	if err != nil && err != errImportRollback {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "import failed"})
+		return
+	}
+
+	sort.SliceStable(rowErrors, func(i, j int) bool {
+		return rowErrors[i].Row < rowErrors[j].Row
+	})
+	if dryRun {
+		c.JSON(http.StatusOK, gin.H{
+			"dryRun": true,
+			"total":  len(rows),
+			"valid":  len(rows) - countImportErrorRows(rowErrors),
+			"errors": rowErrors,
+		})
+		return
+	}
+	if len(rowErrors) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "import has invalid rows",
+			"total":  len(rows),
+			"valid":  len(rows) - countImportErrorRows(rowErrors),
+			"errors": rowErrors,
+		})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"dryRun":  false,
+		"total":   len(rows),
+		"created": created,
+		"errors":  rowErrors,
+	})
+}
+
+func countImportErrorRows(rowErrors []employeeImportError) int {
+	rows := map[int]bool{}
+	for _, rowError := range rowErrors {
+		rows[rowError.Row] = true
+	}
+	return len(rows)
+}
+
+// Export writes the employees matching the List filters as CSV, or as XLSX
+// with format=xlsx, using the columns Import accepts.
+func (h *EmployeeHandler) Export(c *gin.Context) {
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "xlsx" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv or xlsx"})
+		return
+	}
+
+	query, status, message := h.listQuery(c)
+	if message != "" {
+		c.JSON(status, gin.H{"error": message})
+		return
+	}
+	var employees []models.Employee
+	if err := query.Order("employees.last_name asc, employees.first_name asc").Find(&employees).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load employees"})
+		return
+	}
+
+	departmentNames := map[uuid.UUID]string{}
+	var departments []models.Department
+	if err := h.DB.Find(&departments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load employees"})
+		return
+	}
+	for _, department := range departments {
+		departmentNames[department.ID] = department.Name
+	}
+
+	managerIDs := []uuid.UUID{}
+	for _, employee := range employees {
+		if employee.ManagerID != nil {
+			managerIDs = append(managerIDs, *employee.ManagerID)
+		}
+	}
+	managerEmails := map[uuid.UUID]string{}
+	if len(managerIDs) > 0 {
+		var managers []models.Employee
+		if err := h.DB.Unscoped().Where("id IN ?", managerIDs).Find(&managers).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load employees"})
+			return
+		}
+		for _, manager := range managers {
+			managerEmails[manager.ID] = manager.Email
+		}
+	}
+
+	rows := [][]string{employeeFileColumns}
+	for _, employee := range employees {
+		department := ""
+		if employee.DepartmentID != nil {
+			department = departmentNames[*employee.DepartmentID]
+		}
+		managerEmail := ""
+		if employee.ManagerID != nil {
+			managerEmail = managerEmails[*employee.ManagerID]
+		}
+		rows = append(rows, []string{
+			employee.FirstName,
+			employee.LastName,
+			employee.Email,
+			employee.Role,
+			employee.Phone,
+			employee.Position,
+			strconv.FormatFloat(employee.Salary, 'f', 2, 64),
+			employee.HiredAt.Format("2006-01-02"),
+			department,
+			managerEmail,
+			employee.Status,
+		})
+	}
+
+	filename := "employees-" + time.Now().Format("2006-01-02") + "." + format
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	if format == "xlsx" {
+		var buf bytes.Buffer
+		if err := xlsx.Write(&buf, "Employees", rows); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "export failed"})
+			return
+		}
+		c.Data(http.StatusOK, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", buf.Bytes())
+		return
+	}
+
+	for _, row := range rows[1:] {
+		for i := range row {
+			row[i] = escapeCSVCell(row[i])
+		}
+	}
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Status(http.StatusOK)
+	w := csv.NewWriter(c.Writer)
+	_ = w.WriteAll(rows)
+}
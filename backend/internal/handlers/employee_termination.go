@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"erp-backend/internal/models"
+)
+
+const (
+	employeeStatusActive     = "active"
+	employeeStatusOnNotice   = "on_notice"
+	employeeStatusTerminated = "terminated"
+)
+
+type terminateEmployeeRequest struct {
+	TerminationDate string `json:"terminationDate" binding:"required"`
+	Reason          string `json:"reason" binding:"required"`
+}
+
+// Terminate records an employee's exit. A termination date in the future puts
+// the employee on notice; the exit is completed by RunTerminations once the
+// date has passed. Either way, pending leave after the exit date is cancelled
+// and leave balances are prorated up to it straight away.
+func (h *EmployeeHandler) Terminate(c *gin.Context) {
+	var req terminateEmployeeRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.Reason) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "terminationDate and reason required"})
+		return
+	}
+
+	target, status, message := accessibleEmployee(h.DB, c, true)
+	if message != "" {
+		c.JSON(status, gin.H{"error": message})
+		return
+	}
+
+	terminationDate, err := time.Parse("2006-01-02", req.TerminationDate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid terminationDate"})
+		return
+	}
+
+	var employee models.Employee
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&employee, "id = ?", target.ID).Error; err != nil {
+			return err
+		}
+		if employee.Status == employeeStatusTerminated || terminationDate.Before(employee.HiredAt) {
+			return gorm.ErrInvalidData
+		}
+
+		before := employee
+		employee.TerminationDate = &terminationDate
+		employee.TerminationReason = strings.TrimSpace(req.Reason)
+		employee.Status = employeeStatusOnNotice
+		if !terminationDate.After(utcToday()) {
+			employee.Status = employeeStatusTerminated
+		}
+		if err := tx.Save(&employee).Error; err != nil {
+			return err
+		}
+		if err := applyTermination(tx, employee, time.Now()); err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionUpdate, "employee", employee.ID, before, employee)
+	}); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+		if err == gorm.ErrInvalidData {
+			c.JSON(http.StatusConflict, gin.H{"error": "employee already terminated or date before hire date"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "termination failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, employee)
+}
+
+// applyTermination carries out the side effects of employee's termination
+// status. Leave clean-up runs for every exit; login and attendance are only
+// closed once the employee is actually terminated.
+func applyTermination(tx *gorm.DB, employee models.Employee, now time.Time) error {
+	exit := *employee.TerminationDate
+
+	if err := tx.Model(&models.LeaveRequest{}).
+		Where("employee_id = ? AND status = ? AND start_date > ?", employee.ID, "pending", exit).
+		Update("status", leaveStatusCancelled).Error; err != nil {
+		return err
+	}
+
+	var balances []models.LeaveBalance
+	if err := tx.Where("employee_id = ? AND year >= ?", employee.ID, exit.Year()).Find(&balances).Error; err != nil {
+		return err
+	}
+	for _, balance := range balances {
+		policyTotal, err := leavePolicyTotal(tx, balance.Year, balance.Type)
+		if err != nil {
+			return err
+		}
+		total := proratedTotal(policyTotal, employee.HiredAt, employee.TerminationDate, balance.Year)
+		if err := tx.Model(&models.LeaveBalance{}).
+			Where("id = ?", balance.ID).
+			Update("total", total).Error; err != nil {
+			return err
+		}
+	}
+
+	if employee.Status != employeeStatusTerminated {
+		return nil
+	}
+	if err := disableEmployeeLogin(tx, employee.ID, now); err != nil {
+		return err
+	}
+	return closeOpenAttendance(tx, employee.ID, now)
+}
+
+// RunTerminations completes the exit of employees on notice whose
+// termination date has passed.
+func (h *EmployeeHandler) RunTerminations(now time.Time) error {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	var due []uuid.UUID
+	if err := h.DB.Model(&models.Employee{}).
+		Where("status = ? AND termination_date <= ?", employeeStatusOnNotice, today).
+		Pluck("id", &due).Error; err != nil {
+		return err
+	}
+
+	for _, employeeID := range due {
+		if err := h.DB.Transaction(func(tx *gorm.DB) error {
+			var employee models.Employee
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&employee, "id = ?", employeeID).Error; err != nil {
+				return err
+			}
+			if employee.Status != employeeStatusOnNotice {
+				return nil
+			}
+			before := employee
+			employee.Status = employeeStatusTerminated
+			if err := tx.Model(&employee).Update("status", employee.Status).Error; err != nil {
+				return err
+			}
+			if err := applyTermination(tx, employee, now); err != nil {
+				return err
+			}
+			return recordAudit(tx, nil, auditActionUpdate, "employee", employee.ID, before, employee)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"erp-backend/internal/models"
+)
+
+type ExchangeRateHandler struct {
+	DB *gorm.DB
+}
+
+type createExchangeRateRequest struct {
+	Date         string  `json:"date" binding:"required"`
+	FromCurrency string  `json:"fromCurrency" binding:"required"`
+	ToCurrency   string  `json:"toCurrency" binding:"required"`
+	Rate         float64 `json:"rate" binding:"required"`
+}
+
+const baseCurrencySettingKey = "base_currency"
+
+var errMissingExchangeRate = errors.New("missing exchange rate")
+
+func NewExchangeRateHandler(db *gorm.DB) *ExchangeRateHandler {
+	return &ExchangeRateHandler{DB: db}
+}
+
+// baseCurrency returns the company reporting currency, defaulting to USD.
+func baseCurrency(db *gorm.DB) string {
+	values, err := loadSettings(db, baseCurrencySettingKey)
+	if err != nil {
+		return "USD"
+	}
+	if currency, ok := normalizeCurrency(values[baseCurrencySettingKey]); ok {
+		return currency
+	}
+	return "USD"
+}
+
+// currencyConverter converts amounts into the base currency using the most
+// recent rate on or before the given date. Rates are cached per request.
+type currencyConverter struct {
+	db    *gorm.DB
+	base  string
+	rates map[string]float64
+}
+
+func newCurrencyConverter(db *gorm.DB) *currencyConverter {
+	return &currencyConverter{db: db, base: baseCurrency(db), rates: map[string]float64{}}
+}
+
+func (cc *currencyConverter) Convert(amount float64, currency string, on time.Time) (float64, error) {
+	if currency == "" || currency == cc.base {
+		return amount, nil
+	}
+	rate, err := cc.rate(currency, on)
+	if err != nil {
+		return 0, err
+	}
+	return roundMoney(amount * rate), nil
+}
+
+func (cc *currencyConverter) rate(currency string, on time.Time) (float64, error) {
+	day := on.Format("2006-01-02")
+	cacheKey := currency + "|" + day
+	if rate, ok := cc.rates[cacheKey]; ok {
+		return rate, nil
+	}
+
+	var direct models.ExchangeRate
+	err := cc.db.Where("from_currency = ? AND to_currency = ? AND date <= ?", currency, cc.base, day).
+		Order("date desc").First(&direct).Error
+	if err == nil && direct.Rate > 0 {
+		cc.rates[cacheKey] = direct.Rate
+		return direct.Rate, nil
+	}
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return 0, err
+	}
+
+	var inverse models.ExchangeRate
+	err = cc.db.Where("from_currency = ? AND to_currency = ? AND date <= ?", cc.base, currency, day).
+		Order("date desc").First(&inverse).Error
+	if err == nil && inverse.Rate > 0 {
+		rate := 1 / inverse.Rate
+		cc.rates[cacheKey] = rate
+		return rate, nil
+	}
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return 0, err
+	}
+	return 0, errMissingExchangeRate
+}
+
+func (h *ExchangeRateHandler) List(c *gin.Context) {
+	query := h.DB.Model(&models.ExchangeRate{})
+	if currency := c.Query("currency"); currency != "" {
+		normalized, ok := normalizeCurrency(currency)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid currency"})
+			return
+		}
+		query = query.Where("from_currency = ? OR to_currency = ?", normalized, normalized)
+	}
+
+	var rates []models.ExchangeRate
+	if err := query.Order("date desc, from_currency asc, to_currency asc").Find(&rates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load exchange rates"})
+		return
+	}
+	c.JSON(http.StatusOK, rates)
+}
+
+func (h *ExchangeRateHandler) Create(c *gin.Context) {
+	var req createExchangeRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date"})
+		return
+	}
+	from, ok := normalizeCurrency(req.FromCurrency)
+	if !ok || req.FromCurrency == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid fromCurrency"})
+		return
+	}
+	to, ok := normalizeCurrency(req.ToCurrency)
+	if !ok || req.ToCurrency == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid toCurrency"})
+		return
+	}
+	if from == to {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "currencies must differ"})
+		return
+	}
+	if req.Rate <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "rate must be positive"})
+		return
+	}
+
+	var rate models.ExchangeRate
+	err = h.DB.Where("date = ? AND from_currency = ? AND to_currency = ?", date, from, to).First(&rate).Error
+	if err == nil {
+		before := rate
+		rate.Rate = req.Rate
+		if err := h.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Save(&rate).Error; err != nil {
+				return err
+			}
+			return recordAudit(tx, c, auditActionUpdate, "exchange_rate", rate.ID, before, rate)
+		}); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed"})
+			return
+		}
+		c.JSON(http.StatusOK, rate)
+		return
+	} else if err != gorm.ErrRecordNotFound {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "create failed"})
+		return
+	}
+
+	rate = models.ExchangeRate{
+		Date:         date,
+		FromCurrency: from,
+		ToCurrency:   to,
+		Rate:         req.Rate,
+	}
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&rate).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionCreate, "exchange_rate", rate.ID, nil, rate)
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "create failed"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, rate)
+}
+
+func (h *ExchangeRateHandler) Delete(c *gin.Context) {
+	rateID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var rate models.ExchangeRate
+	if err := h.DB.First(&rate, "id = ?", rateID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "exchange rate not found"})
+		return
+	}
+
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.ExchangeRate{}, "id = ?", rateID).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionDelete, "exchange_rate", rate.ID, rate, nil)
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "delete failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
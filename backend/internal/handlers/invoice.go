@@ -1,13 +1,18 @@
 package handlers
 
 import (
+	"errors"
+	"math"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
+	"erp-backend/internal/middleware"
 	"erp-backend/internal/models"
 )
 
@@ -16,25 +21,296 @@ type InvoiceHandler struct {
 }
 
 type createInvoiceRequest struct {
-	Number       string  `json:"number" binding:"required"`
-	CustomerName string  `json:"customerName" binding:"required"`
-	Amount       float64 `json:"amount" binding:"required"`
-	Status       string  `json:"status" binding:"required"`
-	IssuedAt     string  `json:"issuedAt" binding:"required"`
-	DueAt        string  `json:"dueAt" binding:"required"`
+	Number     string               `json:"number"`
+	CustomerID string               `json:"customerId"`
+	Currency   string               `json:"currency"`
+	Status     string               `json:"status"`
+	IssuedAt   string               `json:"issuedAt" binding:"required"`
+	DueAt      string               `json:"dueAt"`
+	Lines      []invoiceLineRequest `json:"lines"`
+}
+
+type invoiceLineRequest struct {
+	Description string  `json:"description" binding:"required"`
+	Quantity    float64 `json:"quantity" binding:"required"`
+	UnitPrice   float64 `json:"unitPrice"`
+	Discount    float64 `json:"discount"`
+	TaxRate     float64 `json:"taxRate"`
+}
+
+const (
+	invoiceStatusDraft         = "draft"
+	invoiceStatusSent          = "sent"
+	invoiceStatusPartiallyPaid = "partially_paid"
+	invoiceStatusPaid          = "paid"
+	invoiceStatusOverdue       = "overdue"
+	invoiceStatusCredited      = "credited"
+	invoiceStatusVoid          = "void"
+)
+
+type voidInvoiceRequest struct {
+	Reason string `json:"reason" binding:"required"`
 }
 
 func NewInvoiceHandler(db *gorm.DB) *InvoiceHandler {
 	return &InvoiceHandler{DB: db}
 }
 
+// normalizeInvoiceStatus maps a client-requested status onto draft or sent.
+// Every later status is derived from payments and the due date, so echoing a
+// derived status back on update just keeps the invoice issued.
+func normalizeInvoiceStatus(value string) (string, bool) {
+	status := strings.ToLower(strings.TrimSpace(value))
+	switch status {
+	case "", invoiceStatusDraft:
+		return invoiceStatusDraft, true
+	case invoiceStatusSent, invoiceStatusPartiallyPaid, invoiceStatusPaid, invoiceStatusOverdue, invoiceStatusCredited:
+		return invoiceStatusSent, true
+	}
+	return "", false
+}
+
+func invoiceBalance(invoice models.Invoice) float64 {
+	return roundMoney(invoice.Amount - invoice.Credited - invoice.AmountPaid)
+}
+
+func deriveInvoiceStatus(invoice models.Invoice, now time.Time) string {
+	if invoice.Status == invoiceStatusVoid {
+		return invoiceStatusVoid
+	}
+	if invoice.Status == invoiceStatusDraft && invoice.AmountPaid <= 0 && invoice.Credited <= 0 {
+		return invoiceStatusDraft
+	}
+	if invoiceBalance(invoice) <= 0 {
+		if invoice.AmountPaid <= 0 && invoice.Credited > 0 {
+			return invoiceStatusCredited
+		}
+		return invoiceStatusPaid
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if invoice.DueAt.Before(today) {
+		return invoiceStatusOverdue
+	}
+	if invoice.AmountPaid > 0 {
+		return invoiceStatusPartiallyPaid
+	}
+	return invoiceStatusSent
+}
+
+// refreshInvoiceStatus recomputes the paid and credited amounts from the
+// payments ledger and credit notes, and stores the derived status.
+func refreshInvoiceStatus(tx *gorm.DB, invoice *models.Invoice) error {
+	var paid float64
+	if err := tx.Model(&models.Payment{}).
+		Where("invoice_id = ?", invoice.ID).
+		Select("COALESCE(SUM(amount),0)").Scan(&paid).Error; err != nil {
+		return err
+	}
+	var credited float64
+	if err := tx.Model(&models.CreditNote{}).
+		Where("invoice_id = ?", invoice.ID).
+		Select("COALESCE(SUM(amount),0)").Scan(&credited).Error; err != nil {
+		return err
+	}
+	invoice.AmountPaid = roundMoney(paid)
+	invoice.Credited = roundMoney(credited)
+	invoice.Status = deriveInvoiceStatus(*invoice, time.Now())
+	return tx.Model(&models.Invoice{}).
+		Where("id = ?", invoice.ID).
+		Updates(map[string]any{
+			"amount_paid": invoice.AmountPaid,
+			"credited":    invoice.Credited,
+			"status":      invoice.Status,
+		}).Error
+}
+
+// resolveInvoiceCustomer finds the customer an invoice is billed to.
+func resolveInvoiceCustomer(db *gorm.DB, customerID string) (models.Customer, string) {
+	var customer models.Customer
+	if strings.TrimSpace(customerID) == "" {
+		return customer, "customerId required"
+	}
+	id, err := uuid.Parse(customerID)
+	if err != nil {
+		return customer, "invalid customerId"
+	}
+	if err := db.First(&customer, "id = ?", id).Error; err != nil {
+		return customer, "customer not found"
+	}
+	return customer, ""
+}
+
+// resolveInvoiceCurrency defaults an invoice to its customer's currency.
+func resolveInvoiceCurrency(value string, customer models.Customer) (string, bool) {
+	if strings.TrimSpace(value) == "" {
+		return normalizeCurrency(customer.Currency)
+	}
+	return normalizeCurrency(value)
+}
+
+func invoiceNumberExists(db *gorm.DB, number string) (bool, error) {
+	var count int64
+	if err := db.Unscoped().Model(&models.Invoice{}).Where("number = ?", number).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func parseInvoiceDueAt(value string, issuedAt time.Time, customer models.Customer) (time.Time, error) {
+	if value == "" {
+		return issuedAt.AddDate(0, 0, customer.PaymentTermsDays), nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+func markOverdueInvoices(db *gorm.DB, now time.Time) error {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return db.Model(&models.Invoice{}).
+		Where("status IN ? AND due_at < ?", []string{invoiceStatusSent, invoiceStatusPartiallyPaid}, today).
+		Update("status", invoiceStatusOverdue).Error
+}
+
+// RunOverdue marks sent and partially paid invoices past their due date as
+// overdue.
+func (h *InvoiceHandler) RunOverdue(now time.Time) error {
+	return markOverdueInvoices(h.DB, now)
+}
+
+func roundMoney(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// buildInvoiceLines validates the requested lines and computes per-line and
+// invoice totals. Every invoice needs at least one line.
+func buildInvoiceLines(req createInvoiceRequest) ([]models.InvoiceLine, string) {
+	items := req.Lines
+	if len(items) == 0 {
+		return nil, "lines required"
+	}
+
+	lines := make([]models.InvoiceLine, 0, len(items))
+	for index, item := range items {
+		description := strings.TrimSpace(item.Description)
+		if description == "" {
+			return nil, "line description required"
+		}
+		if item.Quantity <= 0 {
+			return nil, "line quantity must be positive"
+		}
+		if item.UnitPrice < 0 {
+			return nil, "line unitPrice cannot be negative"
+		}
+		if item.Discount < 0 || item.Discount > 100 {
+			return nil, "line discount must be between 0 and 100"
+		}
+		if item.TaxRate < 0 || item.TaxRate > 100 {
+			return nil, "line taxRate must be between 0 and 100"
+		}
+
+		gross := item.Quantity * item.UnitPrice
+		subtotal := roundMoney(gross - gross*item.Discount/100)
+		taxAmount := roundMoney(subtotal * item.TaxRate / 100)
+		lines = append(lines, models.InvoiceLine{
+			Position:    index + 1,
+			Description: description,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice,
+			Discount:    item.Discount,
+			TaxRate:     item.TaxRate,
+			Subtotal:    subtotal,
+			TaxAmount:   taxAmount,
+			Total:       roundMoney(subtotal + taxAmount),
+		})
+	}
+	return lines, ""
+}
+
+func applyInvoiceTotals(invoice *models.Invoice, lines []models.InvoiceLine) {
+	subtotal := 0.0
+	taxTotal := 0.0
+	for _, line := range lines {
+		subtotal += line.Subtotal
+		taxTotal += line.TaxAmount
+	}
+	invoice.Subtotal = roundMoney(subtotal)
+	invoice.TaxTotal = roundMoney(taxTotal)
+	invoice.Amount = roundMoney(subtotal + taxTotal)
+}
+
+func preloadInvoiceLines(db *gorm.DB) *gorm.DB {
+	return db.Preload("Lines", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position asc")
+	})
+}
+
+var invoiceSortColumns = map[string]string{
+	"createdAt": "created_at",
+	"issuedAt":  "issued_at",
+	"dueAt":     "due_at",
+	"number":    "number",
+	"amount":    "amount",
+	"status":    "status",
+	"customer":  "customer_name",
+}
+
 func (h *InvoiceHandler) List(c *gin.Context) {
-	var invoices []models.Invoice
-	if err := h.DB.Order("created_at desc").Find(&invoices).Error; err != nil {
+	params, message := parseListParams(c, invoiceSortColumns, "createdAt")
+	if message != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return
+	}
+	from, to, message := parseDateRange(c)
+	if message != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return
+	}
+
+	query := h.DB.Model(&models.Invoice{})
+	if c.Query("archived") == "true" {
+		if role, _ := c.Get(middleware.ContextRole); role != "admin" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
+		query = query.Unscoped().Where("invoices.deleted_at IS NOT NULL")
+	}
+	if value := c.Query("status"); value != "" {
+		query = query.Where("invoices.status IN ?", strings.Split(value, ","))
+	}
+	if value := c.Query("customerId"); value != "" {
+		customerID, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid customerId"})
+			return
+		}
+		query = query.Where("invoices.customer_id = ?", customerID)
+	}
+	if value := c.Query("currency"); value != "" {
+		query = query.Where("invoices.currency = ?", strings.ToUpper(value))
+	}
+	if !from.IsZero() {
+		query = query.Where("invoices.issued_at >= ?", from)
+	}
+	if !to.IsZero() {
+		query = query.Where("invoices.issued_at < ?", to)
+	}
+	if value := strings.TrimSpace(c.Query("q")); value != "" {
+		like := containsPattern(value)
+		query = query.Where("invoices.number LIKE ? OR invoices.customer_name LIKE ?", like, like)
+	}
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load invoices"})
+		return
+	}
+
+	invoices := []models.Invoice{}
+	if err := params.apply(preloadInvoiceLines(query), "invoices").Find(&invoices).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load invoices"})
 		return
 	}
-	c.JSON(http.StatusOK, invoices)
+	c.JSON(http.StatusOK, pageResponse(invoices, total, params))
 }
 
 func (h *InvoiceHandler) Create(c *gin.Context) {
@@ -44,32 +320,103 @@ func (h *InvoiceHandler) Create(c *gin.Context) {
 		return
 	}
 
+	var (
+		invoice models.Invoice
+		status  int
+		message string
+	)
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		invoice, status, message = createInvoice(tx, req)
+		if message != "" {
+			return errors.New(message)
+		}
+		return recordAudit(tx, c, auditActionCreate, "invoice", invoice.ID, nil, invoice)
+	}); err != nil {
+		if message == "" {
+			status, message = http.StatusInternalServerError, "create failed"
+		}
+		c.JSON(status, gin.H{"error": message})
+		return
+	}
+
+	c.JSON(http.StatusCreated, invoice)
+}
+
+// createInvoice validates req and stores a new invoice with its lines,
+// allocating a number when none is given. On failure it returns the HTTP
+// status and client-facing message. db may be a transaction.
+func createInvoice(db *gorm.DB, req createInvoiceRequest) (models.Invoice, int, string) {
+	var invoice models.Invoice
+
 	issuedAt, err := time.Parse("2006-01-02", req.IssuedAt)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid issuedAt"})
-		return
+		return invoice, http.StatusBadRequest, "invalid issuedAt"
+	}
+	status, validStatus := normalizeInvoiceStatus(req.Status)
+	if !validStatus {
+		return invoice, http.StatusBadRequest, "invalid status"
 	}
-	dueAt, err := time.Parse("2006-01-02", req.DueAt)
+
+	lines, lineErr := buildInvoiceLines(req)
+	if lineErr != "" {
+		return invoice, http.StatusBadRequest, lineErr
+	}
+
+	customer, customerErr := resolveInvoiceCustomer(db, req.CustomerID)
+	if customerErr != "" {
+		return invoice, http.StatusBadRequest, customerErr
+	}
+
+	dueAt, err := parseInvoiceDueAt(req.DueAt, issuedAt, customer)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid dueAt"})
-		return
+		return invoice, http.StatusBadRequest, "invalid dueAt"
+	}
+
+	currency, validCurrency := resolveInvoiceCurrency(req.Currency, customer)
+	if !validCurrency {
+		return invoice, http.StatusBadRequest, "invalid currency"
 	}
 
-	invoice := models.Invoice{
-		Number:       req.Number,
-		CustomerName: req.CustomerName,
-		Amount:       req.Amount,
-		Status:       req.Status,
+	invoice = models.Invoice{
+		Number:       strings.TrimSpace(req.Number),
+		CustomerID:   &customer.ID,
+		CustomerName: customer.Name,
+		Currency:     currency,
+		Status:       status,
 		IssuedAt:     issuedAt,
 		DueAt:        dueAt,
+		Lines:        lines,
 	}
+	applyInvoiceTotals(&invoice, lines)
+	invoice.Status = deriveInvoiceStatus(invoice, time.Now())
 
-	if err := h.DB.Create(&invoice).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "create failed"})
-		return
+	if err := db.Transaction(func(tx *gorm.DB) error {
+		if invoice.Number == "" {
+			number, err := allocateSequenceNumber(tx, invoiceSequenceKey, issuedAt, func(candidate string) (bool, error) {
+				return invoiceNumberExists(tx, candidate)
+			})
+			if err != nil {
+				return err
+			}
+			invoice.Number = number
+		} else {
+			taken, err := invoiceNumberExists(tx, invoice.Number)
+			if err != nil {
+				return err
+			}
+			if taken {
+				return gorm.ErrDuplicatedKey
+			}
+		}
+		return tx.Create(&invoice).Error
+	}); err != nil {
+		if err == gorm.ErrDuplicatedKey {
+			return invoice, http.StatusConflict, "invoice number already exists"
+		}
+		return invoice, http.StatusInternalServerError, "create failed"
 	}
 
-	c.JSON(http.StatusCreated, invoice)
+	return invoice, http.StatusCreated, ""
 }
 
 func (h *InvoiceHandler) Update(c *gin.Context) {
@@ -90,26 +437,90 @@ func (h *InvoiceHandler) Update(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid issuedAt"})
 		return
 	}
-	dueAt, err := time.Parse("2006-01-02", req.DueAt)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid dueAt"})
+	status, validStatus := normalizeInvoiceStatus(req.Status)
+	if !validStatus {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid status"})
+		return
+	}
+
+	lines, lineErr := buildInvoiceLines(req)
+	if lineErr != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": lineErr})
 		return
 	}
 
 	var invoice models.Invoice
-	if err := h.DB.First(&invoice, "id = ?", invoiceID).Error; err != nil {
+	if err := preloadInvoiceLines(h.DB).First(&invoice, "id = ?", invoiceID).Error; err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "invoice not found"})
 		return
 	}
+	if invoice.Status != invoiceStatusDraft {
+		c.JSON(http.StatusConflict, gin.H{"error": "issued invoices cannot be edited"})
+		return
+	}
+	before := invoice
+
+	customer, customerErr := resolveInvoiceCustomer(h.DB, req.CustomerID)
+	if customerErr != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": customerErr})
+		return
+	}
 
-	invoice.Number = req.Number
-	invoice.CustomerName = req.CustomerName
-	invoice.Amount = req.Amount
-	invoice.Status = req.Status
+	dueAt, err := parseInvoiceDueAt(req.DueAt, issuedAt, customer)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid dueAt"})
+		return
+	}
+
+	currency, validCurrency := resolveInvoiceCurrency(req.Currency, customer)
+	if !validCurrency {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid currency"})
+		return
+	}
+
+	if number := strings.TrimSpace(req.Number); number != "" && number != invoice.Number {
+		taken, err := invoiceNumberExists(h.DB, number)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed"})
+			return
+		}
+		if taken {
+			c.JSON(http.StatusConflict, gin.H{"error": "invoice number already exists"})
+			return
+		}
+		invoice.Number = number
+	}
+	invoice.CustomerID = &customer.ID
+	invoice.CustomerName = customer.Name
+	invoice.Currency = currency
+	invoice.Status = status
 	invoice.IssuedAt = issuedAt
 	invoice.DueAt = dueAt
+	applyInvoiceTotals(&invoice, lines)
+	if invoice.Amount < roundMoney(invoice.AmountPaid+invoice.Credited) {
+		c.JSON(http.StatusConflict, gin.H{"error": "invoice total cannot be less than the amount paid and credited"})
+		return
+	}
 
-	if err := h.DB.Save(&invoice).Error; err != nil {
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("invoice_id = ?", invoice.ID).Delete(&models.InvoiceLine{}).Error; err != nil {
+			return err
+		}
+		for index := range lines {
+			lines[index].InvoiceID = invoice.ID
+		}
+		if err := tx.Create(&lines).Error; err != nil {
+			return err
+		}
+		if err := tx.Omit("Lines", "Payments", "CreditNotes").Save(&invoice).Error; err != nil {
+			return err
+		}
+		if err := refreshInvoiceStatus(tx, &invoice); err != nil {
+			return err
+		}
+		invoice.Lines = lines
+		return recordAudit(tx, c, auditActionUpdate, "invoice", invoice.ID, before, invoice)
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed"})
 		return
 	}
@@ -117,6 +528,38 @@ func (h *InvoiceHandler) Update(c *gin.Context) {
 	c.JSON(http.StatusOK, invoice)
 }
 
+func (h *InvoiceHandler) PDF(c *gin.Context) {
+	invoiceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var invoice models.Invoice
+	if err := preloadInvoiceLines(h.DB).First(&invoice, "id = ?", invoiceID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "invoice not found"})
+		return
+	}
+
+	var customer models.Customer
+	if invoice.CustomerID != nil {
+		_ = h.DB.First(&customer, "id = ?", *invoice.CustomerID).Error
+	}
+	if customer.Name == "" {
+		customer.Name = invoice.CustomerName
+	}
+
+	company, err := loadCompanyProfile(h.DB)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load company"})
+		return
+	}
+
+	filename := "invoice-" + invoice.Number + ".pdf"
+	c.Header("Content-Disposition", `attachment; filename="`+strings.ReplaceAll(filename, `"`, "")+`"`)
+	c.Data(http.StatusOK, "application/pdf", renderInvoicePDF(invoice, customer, company))
+}
+
 func (h *InvoiceHandler) Delete(c *gin.Context) {
 	invoiceID, err := uuid.Parse(c.Param("id"))
 	if err != nil {
@@ -124,10 +567,120 @@ func (h *InvoiceHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.DB.Delete(&models.Invoice{}, "id = ?", invoiceID).Error; err != nil {
+	var invoice models.Invoice
+	if err := preloadInvoiceLines(h.DB).First(&invoice, "id = ?", invoiceID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "invoice not found"})
+		return
+	}
+	if invoice.Status != invoiceStatusDraft {
+		c.JSON(http.StatusConflict, gin.H{"error": "only draft invoices can be deleted, void it instead"})
+		return
+	}
+
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.Invoice{}, "id = ? AND status = ?", invoiceID, invoiceStatusDraft).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionDelete, "invoice", invoice.ID, invoice, nil)
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "delete failed"})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
 }
+
+func (h *InvoiceHandler) Restore(c *gin.Context) {
+	invoiceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var invoice models.Invoice
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := preloadInvoiceLines(tx.Unscoped()).Where("deleted_at IS NOT NULL").First(&invoice, "id = ?", invoiceID).Error; err != nil {
+			return err
+		}
+		before := invoice
+		if err := tx.Unscoped().Model(&models.Invoice{}).Where("id = ?", invoice.ID).Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		invoice.DeletedAt = gorm.DeletedAt{}
+		return recordAudit(tx, c, auditActionRestore, "invoice", invoice.ID, before, invoice)
+	}); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "archived invoice not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "restore failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, invoice)
+}
+
+func (h *InvoiceHandler) Void(c *gin.Context) {
+	invoiceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req voidInvoiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.Reason) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "reason required"})
+		return
+	}
+
+	actorID, ok := c.Get(middleware.ContextUserID)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+	actorUUID, err := uuid.Parse(actorID.(string))
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var invoice models.Invoice
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&invoice, "id = ?", invoiceID).Error; err != nil {
+			return err
+		}
+		if invoice.Status == invoiceStatusVoid || invoice.AmountPaid > 0 {
+			return gorm.ErrInvalidData
+		}
+		before := invoice
+		now := time.Now()
+		invoice.Status = invoiceStatusVoid
+		invoice.VoidedAt = &now
+		invoice.VoidedBy = &actorUUID
+		invoice.VoidReason = strings.TrimSpace(req.Reason)
+		if err := tx.Model(&models.Invoice{}).
+			Where("id = ?", invoice.ID).
+			Updates(map[string]any{
+				"status":      invoice.Status,
+				"voided_at":   invoice.VoidedAt,
+				"voided_by":   invoice.VoidedBy,
+				"void_reason": invoice.VoidReason,
+			}).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionUpdate, "invoice", invoice.ID, before, invoice)
+	}); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "invoice not found"})
+			return
+		}
+		if err == gorm.ErrInvalidData {
+			c.JSON(http.StatusConflict, gin.H{"error": "invoice is void or has payments"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "void failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, invoice)
+}
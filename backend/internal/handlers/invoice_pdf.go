@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"erp-backend/internal/models"
+	"erp-backend/internal/pdf"
+)
+
+type companyProfile struct {
+	Name    string
+	Address string
+	Email   string
+	Phone   string
+	TaxID   string
+	Logo    image.Image
+}
+
+const (
+	pdfMargin     = 50.0
+	pdfBottomEdge = pdf.PageHeight - 60
+)
+
+func loadCompanyProfile(db *gorm.DB) (companyProfile, error) {
+	values, err := loadSettings(db,
+		companyNameSettingKey, companyAddrSettingKey, companyEmailSettingKey,
+		companyPhoneSettingKey, companyTaxIDSettingKey, expandedLogoSettingKey, logoSettingKey)
+	if err != nil {
+		return companyProfile{}, err
+	}
+
+	profile := companyProfile{
+		Name:    values[companyNameSettingKey],
+		Address: values[companyAddrSettingKey],
+		Email:   values[companyEmailSettingKey],
+		Phone:   values[companyPhoneSettingKey],
+		TaxID:   values[companyTaxIDSettingKey],
+	}
+	logo := values[expandedLogoSettingKey]
+	if logo == "" {
+		logo = values[logoSettingKey]
+	}
+	if img, ok := decodeDataURLImage(logo); ok {
+		profile.Logo = img
+	}
+	return profile, nil
+}
+
+// decodeDataURLImage decodes logos stored as base64 data URLs. Remote URLs are
+// never fetched so rendering works offline.
+func decodeDataURLImage(value string) (image.Image, bool) {
+	if !strings.HasPrefix(value, "data:") {
+		return nil, false
+	}
+	comma := strings.Index(value, ",")
+	if comma < 0 || !strings.HasSuffix(value[:comma], ";base64") {
+		return nil, false
+	}
+	raw, err := base64.StdEncoding.DecodeString(value[comma+1:])
+	if err != nil {
+		return nil, false
+	}
+	img, _, err := image.Decode(strings.NewReader(string(raw)))
+	if err != nil {
+		return nil, false
+	}
+	return img, true
+}
+
+// drawCompanyHeader renders the logo on the left and the company details
+// right-aligned, returning the y position below the header.
+func drawCompanyHeader(doc *pdf.Document, company companyProfile) float64 {
+	top := pdfMargin
+	logoBottom := top
+	if company.Logo != nil {
+		bounds := company.Logo.Bounds()
+		width, height := 160.0, 160.0*float64(bounds.Dy())/float64(bounds.Dx())
+		if height > 60 {
+			width, height = 60*float64(bounds.Dx())/float64(bounds.Dy()), 60
+		}
+		if err := doc.Image(company.Logo, pdfMargin, top, width, height); err == nil {
+			logoBottom = top + height
+		}
+	}
+
+	right := pdf.PageWidth - pdfMargin
+	y := top + 12
+	if company.Name != "" {
+		doc.TextRight(right, y, 13, true, company.Name)
+		y += 16
+	}
+	details := []string{}
+	for _, line := range strings.Split(company.Address, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			details = append(details, line)
+		}
+	}
+	if company.Email != "" {
+		details = append(details, company.Email)
+	}
+	if company.Phone != "" {
+		details = append(details, company.Phone)
+	}
+	if company.TaxID != "" {
+		details = append(details, "Tax ID: "+company.TaxID)
+	}
+	for _, line := range details {
+		doc.TextRight(right, y, 9, false, line)
+		y += 12
+	}
+
+	if logoBottom > y {
+		y = logoBottom
+	}
+	return y + 20
+}
+
+func formatMoney(value float64) string {
+	return fmt.Sprintf("%.2f", value)
+}
+
+func renderInvoicePDF(invoice models.Invoice, customer models.Customer, company companyProfile) []byte {
+	doc := pdf.New()
+	right := pdf.PageWidth - pdfMargin
+
+	y := drawCompanyHeader(doc, company)
+	title := "INVOICE"
+	if invoice.Status == invoiceStatusVoid {
+		title = "INVOICE (VOID)"
+	}
+	doc.Text(pdfMargin, y, 22, true, title)
+	y += 26
+
+	meta := [][2]string{
+		{"Invoice number", invoice.Number},
+		{"Issued", invoice.IssuedAt.Format("2006-01-02")},
+		{"Due", invoice.DueAt.Format("2006-01-02")},
+		{"Currency", invoice.Currency},
+		{"Status", strings.ReplaceAll(invoice.Status, "_", " ")},
+	}
+	for _, row := range meta {
+		doc.Text(pdfMargin, y, 10, true, row[0])
+		doc.Text(pdfMargin+100, y, 10, false, row[1])
+		y += 14
+	}
+
+	y += 10
+	doc.Text(pdfMargin, y, 10, true, "Bill to")
+	y += 14
+	doc.Text(pdfMargin, y, 11, false, customer.Name)
+	y += 14
+	billing := []string{}
+	for _, line := range strings.Split(customer.Address, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			billing = append(billing, line)
+		}
+	}
+	if customer.BillingEmail != "" {
+		billing = append(billing, customer.BillingEmail)
+	}
+	if customer.TaxID != "" {
+		billing = append(billing, "Tax ID: "+customer.TaxID)
+	}
+	for _, line := range billing {
+		doc.Text(pdfMargin, y, 9, false, line)
+		y += 12
+	}
+	y += 12
+
+	columns := []struct {
+		title string
+		right float64
+	}{
+		{"Qty", 330},
+		{"Unit price", 400},
+		{"Disc %", 445},
+		{"Tax %", 490},
+		{"Amount", right},
+	}
+	drawTableHeader := func() {
+		doc.FillRect(pdfMargin, y-12, right-pdfMargin, 18, 0.9)
+		doc.Text(pdfMargin+4, y, 9, true, "Description")
+		for _, column := range columns {
+			doc.TextRight(column.right-4, y, 9, true, column.title)
+		}
+		y += 20
+	}
+	drawTableHeader()
+
+	for _, line := range invoice.Lines {
+		if y > pdfBottomEdge {
+			doc.AddPage()
+			y = pdfMargin + 12
+			drawTableHeader()
+		}
+		doc.Text(pdfMargin+4, y, 9, false, pdf.Truncate(line.Description, 250, 9, false))
+		values := []string{
+			fmt.Sprintf("%g", line.Quantity),
+			formatMoney(line.UnitPrice),
+			fmt.Sprintf("%g", line.Discount),
+			fmt.Sprintf("%g", line.TaxRate),
+			formatMoney(line.Subtotal),
+		}
+		for index, value := range values {
+			doc.TextRight(columns[index].right-4, y, 9, false, value)
+		}
+		y += 16
+	}
+
+	if y > pdfBottomEdge-80 {
+		doc.AddPage()
+		y = pdfMargin + 12
+	}
+	doc.Line(pdfMargin, y-8, right, y-8, 0.5)
+	y += 8
+
+	totals := []struct {
+		label string
+		value float64
+		bold  bool
+	}{
+		{"Subtotal", invoice.Subtotal, false},
+		{"Tax", invoice.TaxTotal, false},
+		{"Total", invoice.Amount, true},
+		{"Credited", invoice.Credited, false},
+		{"Paid", invoice.AmountPaid, false},
+		{"Balance due", invoiceBalance(invoice), true},
+	}
+	for _, total := range totals {
+		doc.TextRight(440, y, 10, total.bold, total.label)
+		doc.TextRight(right-4, y, 10, total.bold, formatMoney(total.value))
+		y += 15
+	}
+
+	return doc.Bytes()
+}
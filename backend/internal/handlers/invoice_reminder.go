@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"erp-backend/internal/config"
+	"erp-backend/internal/email"
+	"erp-backend/internal/models"
+)
+
+type InvoiceReminderHandler struct {
+	DB  *gorm.DB
+	Cfg config.Config
+}
+
+const reminderOffsetsSettingKey = "invoice_reminder_offsets"
+
+func NewInvoiceReminderHandler(db *gorm.DB, cfg config.Config) *InvoiceReminderHandler {
+	return &InvoiceReminderHandler{DB: db, Cfg: cfg}
+}
+
+// parseReminderOffsets reads a comma separated list of days after the due
+// date, returning them sorted and de-duplicated.
+func parseReminderOffsets(value string) ([]int, bool) {
+	offsets := []int{}
+	seen := map[int]bool{}
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		offset, err := strconv.Atoi(part)
+		if err != nil || offset < 1 || offset > 365 {
+			return nil, false
+		}
+		if !seen[offset] {
+			seen[offset] = true
+			offsets = append(offsets, offset)
+		}
+	}
+	sort.Ints(offsets)
+	return offsets, true
+}
+
+func formatReminderOffsets(offsets []int) string {
+	parts := make([]string, 0, len(offsets))
+	for _, offset := range offsets {
+		parts = append(parts, strconv.Itoa(offset))
+	}
+	return strings.Join(parts, ",")
+}
+
+func reminderOffsets(db *gorm.DB) []int {
+	values, err := loadSettings(db, reminderOffsetsSettingKey)
+	if err != nil {
+		return []int{}
+	}
+	offsets, ok := parseReminderOffsets(values[reminderOffsetsSettingKey])
+	if !ok {
+		return []int{}
+	}
+	return offsets
+}
+
+func daysOverdue(dueAt time.Time, today time.Time) int {
+	dueDate := time.Date(dueAt.Year(), dueAt.Month(), dueAt.Day(), 0, 0, 0, 0, time.UTC)
+	return int(today.Sub(dueDate).Hours() / 24)
+}
+
+func (h *InvoiceReminderHandler) List(c *gin.Context) {
+	invoiceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var reminders []models.InvoiceReminder
+	if err := h.DB.Where("invoice_id = ?", invoiceID).Order("offset_days asc").Find(&reminders).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load reminders"})
+		return
+	}
+	c.JSON(http.StatusOK, reminders)
+}
+
+// RunDue marks invoices past their due date as overdue and emails at most one
+// reminder per invoice per run: the largest configured offset that has been
+// reached and is not yet covered by a recorded reminder. A reminder row is
+// claimed before the email is sent, so restarts and concurrent runs never
+// send the same reminder twice.
+func (h *InvoiceReminderHandler) RunDue(now time.Time) error {
+	if err := markOverdueInvoices(h.DB, now); err != nil {
+		return err
+	}
+
+	offsets := reminderOffsets(h.DB)
+	if len(offsets) == 0 {
+		return nil
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	earliestDue := today.AddDate(0, 0, -offsets[0])
+
+	var invoices []models.Invoice
+	if err := preloadInvoiceLines(h.DB).
+		Where("status = ? AND customer_id IS NOT NULL AND due_at < ?", invoiceStatusOverdue, earliestDue.AddDate(0, 0, 1)).
+		Find(&invoices).Error; err != nil {
+		return err
+	}
+
+	for _, invoice := range invoices {
+		overdue := daysOverdue(invoice.DueAt, today)
+		target := 0
+		for _, offset := range offsets {
+			if offset <= overdue {
+				target = offset
+			}
+		}
+		if target == 0 {
+			continue
+		}
+
+		var covered int64
+		if err := h.DB.Model(&models.InvoiceReminder{}).
+			Where("invoice_id = ? AND offset_days >= ?", invoice.ID, target).
+			Count(&covered).Error; err != nil {
+			return err
+		}
+		if covered > 0 {
+			continue
+		}
+
+		var customer models.Customer
+		if err := h.DB.First(&customer, "id = ?", *invoice.CustomerID).Error; err != nil || customer.BillingEmail == "" {
+			continue
+		}
+
+		reminder := models.InvoiceReminder{
+			InvoiceID:  invoice.ID,
+			OffsetDays: target,
+			Recipient:  customer.BillingEmail,
+			SentAt:     time.Now(),
+		}
+		if err := h.DB.Create(&reminder).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				// Another instance claimed this reminder first.
+				continue
+			}
+			return err
+		}
+
+		if err := h.sendReminder(invoice, customer, overdue); err != nil {
+			log.Printf("invoice reminder %s failed: %v", invoice.Number, err)
+			_ = h.DB.Delete(&models.InvoiceReminder{}, "id = ?", reminder.ID).Error
+		}
+	}
+	return nil
+}
+
+func (h *InvoiceReminderHandler) sendReminder(invoice models.Invoice, customer models.Customer, overdue int) error {
+	company, err := loadCompanyProfile(h.DB)
+	if err != nil {
+		return err
+	}
+	sender := company.Name
+	if sender == "" {
+		sender = "WorkFlow ERP"
+	}
+
+	subject := fmt.Sprintf("Payment reminder: invoice %s is %d days overdue", invoice.Number, overdue)
+	body := "Dear " + customer.Name + ",\n\n" +
+		"Our records show that invoice " + invoice.Number + " was due on " + invoice.DueAt.Format("2006-01-02") +
+		" and " + formatMoney(invoiceBalance(invoice)) + " " + invoice.Currency + " is still outstanding.\n" +
+		"Please arrange payment at your earliest convenience. If you have already paid, please disregard this message.\n\n" +
+		"Kind regards,\n" + sender
+	attachment := email.Attachment{
+		Filename:    "invoice-" + invoice.Number + ".pdf",
+		ContentType: "application/pdf",
+		Data:        renderInvoicePDF(invoice, customer, company),
+	}
+	return email.SendMessage(smtpConfig(h.Cfg), customer.BillingEmail, subject, body, attachment)
+}
@@ -42,6 +42,11 @@ type leavePolicyUpdateItem struct {
 	Total float64 `json:"total" binding:"required"`
 }
 
+const leaveStatusCancelled = "cancelled"
+
+// inactiveLeaveStatuses are request states that no longer reserve days.
+var inactiveLeaveStatuses = []string{"rejected", leaveStatusCancelled}
+
 func NewLeaveHandler(db *gorm.DB) *LeaveHandler {
 	return &LeaveHandler{DB: db}
 }
@@ -57,6 +62,14 @@ func (h *LeaveHandler) ListRequests(c *gin.Context) {
 		}
 		query = query.Where("employee_id = ?", employeeID)
 	}
+	reports, scoped, err := managerScope(h.DB, c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load leaves"})
+		return
+	}
+	if scoped {
+		query = query.Where("employee_id IN ?", reports)
+	}
 
 	if employeeID := c.Query("employeeId"); employeeID != "" {
 		id, err := uuid.Parse(employeeID)
@@ -122,6 +135,10 @@ func (h *LeaveHandler) CreateRequest(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employeeId"})
 		return
 	}
+	if status, message := employeeAccessError(h.DB, c, employeeID); message != "" {
+		c.JSON(status, gin.H{"error": message})
+		return
+	}
 
 	startDate, err := time.Parse("2006-01-02", req.StartDate)
 	if err != nil {
@@ -148,9 +165,17 @@ func (h *LeaveHandler) CreateRequest(c *gin.Context) {
 		return
 	}
 
+	if message := leaveEmploymentError(h.DB, employeeID, endDate); message != "" {
+		c.JSON(http.StatusConflict, gin.H{"error": message})
+		return
+	}
+	if payrollPeriodLockedResponse(c, h.DB, startDate, endDate) {
+		return
+	}
+
 	var overlap int64
 	if err := h.DB.Model(&models.LeaveRequest{}).
-		Where("employee_id = ? AND status != ? AND start_date <= ? AND end_date >= ?", employeeID, "rejected", endDate, startDate).
+		Where("employee_id = ? AND status NOT IN ? AND start_date <= ? AND end_date >= ?", employeeID, inactiveLeaveStatuses, endDate, startDate).
 		Count(&overlap).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "leave check failed"})
 		return
@@ -180,7 +205,12 @@ func (h *LeaveHandler) CreateRequest(c *gin.Context) {
 		Status:     "pending",
 	}
 
-	if err := h.DB.Create(&request).Error; err != nil {
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&request).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionCreate, "leave_request", request.ID, nil, request)
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "create failed"})
 		return
 	}
@@ -200,7 +230,19 @@ func (h *LeaveHandler) Approve(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "leave not found"})
 		return
 	}
+	if _, status, message := accessibleEmployeeByID(h.DB, c, request.EmployeeID, true); message != "" {
+		c.JSON(status, gin.H{"error": message})
+		return
+	}
 	previousStatus := request.Status
+	before := request
+	if payrollPeriodLockedResponse(c, h.DB, request.StartDate, request.EndDate) {
+		return
+	}
+	if previousStatus == leaveStatusCancelled {
+		c.JSON(http.StatusConflict, gin.H{"error": "leave is cancelled"})
+		return
+	}
 
 	balance, err := h.ensureBalance(request.EmployeeID, request.StartDate.Year(), request.Type)
 	if err != nil {
@@ -240,6 +282,9 @@ func (h *LeaveHandler) Approve(c *gin.Context) {
 		if err := tx.Save(&request).Error; err != nil {
 			return err
 		}
+		if err := recordAudit(tx, c, auditActionUpdate, "leave_request", request.ID, before, request); err != nil {
+			return err
+		}
 		return tx.Save(&balance).Error
 	}); err != nil {
 		if err == gorm.ErrInvalidData {
@@ -265,7 +310,19 @@ func (h *LeaveHandler) Reject(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "leave not found"})
 		return
 	}
+	if _, status, message := accessibleEmployeeByID(h.DB, c, request.EmployeeID, true); message != "" {
+		c.JSON(status, gin.H{"error": message})
+		return
+	}
 	previousStatus := request.Status
+	before := request
+	if payrollPeriodLockedResponse(c, h.DB, request.StartDate, request.EndDate) {
+		return
+	}
+	if previousStatus == leaveStatusCancelled {
+		c.JSON(http.StatusConflict, gin.H{"error": "leave is cancelled"})
+		return
+	}
 
 	balance, err := h.ensureBalance(request.EmployeeID, request.StartDate.Year(), request.Type)
 	if err != nil {
@@ -289,6 +346,9 @@ func (h *LeaveHandler) Reject(c *gin.Context) {
 		if err := tx.Save(&request).Error; err != nil {
 			return err
 		}
+		if err := recordAudit(tx, c, auditActionUpdate, "leave_request", request.ID, before, request); err != nil {
+			return err
+		}
 		return tx.Save(&balance).Error
 	}); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "reject failed"})
@@ -310,7 +370,19 @@ func (h *LeaveHandler) MarkPending(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "leave not found"})
 		return
 	}
+	if _, status, message := accessibleEmployeeByID(h.DB, c, request.EmployeeID, true); message != "" {
+		c.JSON(status, gin.H{"error": message})
+		return
+	}
 	previousStatus := request.Status
+	before := request
+	if payrollPeriodLockedResponse(c, h.DB, request.StartDate, request.EndDate) {
+		return
+	}
+	if previousStatus == leaveStatusCancelled {
+		c.JSON(http.StatusConflict, gin.H{"error": "leave is cancelled"})
+		return
+	}
 
 	balance, err := h.ensureBalance(request.EmployeeID, request.StartDate.Year(), request.Type)
 	if err != nil {
@@ -333,6 +405,9 @@ func (h *LeaveHandler) MarkPending(c *gin.Context) {
 		if err := tx.Save(&request).Error; err != nil {
 			return err
 		}
+		if err := recordAudit(tx, c, auditActionUpdate, "leave_request", request.ID, before, request); err != nil {
+			return err
+		}
 		return tx.Save(&balance).Error
 	}); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "pending update failed"})
@@ -360,13 +435,9 @@ func (h *LeaveHandler) UpdateRequest(c *gin.Context) {
 		return
 	}
 
-	role, _ := c.Get(middleware.ContextRole)
-	if role == "employee" {
-		employeeID, ok := c.Get(middleware.ContextEmployeeID)
-		if !ok || employeeID == "" || request.EmployeeID.String() != employeeID.(string) {
-			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
-			return
-		}
+	if status, message := employeeAccessError(h.DB, c, request.EmployeeID); message != "" {
+		c.JSON(status, gin.H{"error": message})
+		return
 	}
 
 	var req updateLeaveRequest
@@ -407,10 +478,19 @@ func (h *LeaveHandler) UpdateRequest(c *gin.Context) {
 		return
 	}
 
+	if message := leaveEmploymentError(h.DB, request.EmployeeID, endDate); message != "" {
+		c.JSON(http.StatusConflict, gin.H{"error": message})
+		return
+	}
+	if payrollPeriodLockedResponse(c, h.DB, request.StartDate, request.EndDate) ||
+		payrollPeriodLockedResponse(c, h.DB, startDate, endDate) {
+		return
+	}
+
 	var overlap int64
 	if err := h.DB.Model(&models.LeaveRequest{}).
-		Where("employee_id = ? AND id <> ? AND status != ? AND start_date <= ? AND end_date >= ?",
-			request.EmployeeID, request.ID, "rejected", endDate, startDate).
+		Where("employee_id = ? AND id <> ? AND status NOT IN ? AND start_date <= ? AND end_date >= ?",
+			request.EmployeeID, request.ID, inactiveLeaveStatuses, endDate, startDate).
 		Count(&overlap).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "leave check failed"})
 		return
@@ -430,13 +510,19 @@ func (h *LeaveHandler) UpdateRequest(c *gin.Context) {
 		return
 	}
 
+	before := request
 	request.Type = req.Type
 	request.StartDate = startDate
 	request.EndDate = endDate
 	request.Days = float64(days)
 	request.Reason = req.Reason
 
-	if err := h.DB.Save(&request).Error; err != nil {
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&request).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionUpdate, "leave_request", request.ID, before, request)
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed"})
 		return
 	}
@@ -457,21 +543,25 @@ func (h *LeaveHandler) DeleteRequest(c *gin.Context) {
 		return
 	}
 
-	role, _ := c.Get(middleware.ContextRole)
-	if role == "employee" {
-		employeeID, ok := c.Get(middleware.ContextEmployeeID)
-		if !ok || employeeID == "" || request.EmployeeID.String() != employeeID.(string) {
-			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
-			return
-		}
+	if status, message := employeeAccessError(h.DB, c, request.EmployeeID); message != "" {
+		c.JSON(status, gin.H{"error": message})
+		return
 	}
 
 	if request.Status == "approved" {
 		c.JSON(http.StatusConflict, gin.H{"error": "approved leave cannot be deleted"})
 		return
 	}
+	if payrollPeriodLockedResponse(c, h.DB, request.StartDate, request.EndDate) {
+		return
+	}
 
-	if err := h.DB.Delete(&models.LeaveRequest{}, "id = ?", requestID).Error; err != nil {
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.LeaveRequest{}, "id = ?", requestID).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionDelete, "leave_request", request.ID, request, nil)
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "delete failed"})
 		return
 	}
@@ -493,6 +583,15 @@ func (h *LeaveHandler) ListBalances(c *gin.Context) {
 		year = start.Year()
 	}
 
+	reports, scoped, err := managerScope(h.DB, c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load balances"})
+		return
+	}
+	if scoped {
+		query = query.Where("employee_id IN ?", reports)
+	}
+
 	var targetEmployeeIDs []uuid.UUID
 	if role == "employee" {
 		employeeID, ok := c.Get(middleware.ContextEmployeeID)
@@ -515,6 +614,8 @@ func (h *LeaveHandler) ListBalances(c *gin.Context) {
 		}
 		targetEmployeeIDs = []uuid.UUID{id}
 		query = query.Where("employee_id = ?", id)
+	} else if scoped {
+		targetEmployeeIDs = reports
 	} else {
 		var employees []models.Employee
 		if err := h.DB.Find(&employees).Error; err != nil {
@@ -570,7 +671,7 @@ func (h *LeaveHandler) ListBalances(c *gin.Context) {
 				key := policyKey{year: balance.Year, kind: balance.Type}
 				policyTotal, ok := policyCache[key]
 				if !ok {
-					total, err := h.getPolicyTotal(balance.Year, balance.Type)
+					total, err := leavePolicyTotal(h.DB, balance.Year, balance.Type)
 					if err != nil {
 						continue
 					}
@@ -578,7 +679,7 @@ func (h *LeaveHandler) ListBalances(c *gin.Context) {
 					policyCache[key] = total
 				}
 
-				desiredTotal := proratedTotal(policyTotal, employee.HiredAt, balance.Year)
+				desiredTotal := proratedTotal(policyTotal, employee.HiredAt, employee.TerminationDate, balance.Year)
 				if desiredTotal != balance.Total {
 					_ = h.DB.Model(&models.LeaveBalance{}).
 						Where("id = ?", balance.ID).
@@ -649,14 +750,21 @@ func (h *LeaveHandler) UpdatePolicies(c *gin.Context) {
 					if err := tx.Create(&newPolicy).Error; err != nil {
 						return err
 					}
+					if err := recordAudit(tx, c, auditActionCreate, "leave_policy", newPolicy.ID, nil, newPolicy); err != nil {
+						return err
+					}
 				} else {
 					return err
 				}
 			} else {
+				before := existing
 				existing.Total = policy.Total
 				if err := tx.Save(&existing).Error; err != nil {
 					return err
 				}
+				if err := recordAudit(tx, c, auditActionUpdate, "leave_policy", existing.ID, before, existing); err != nil {
+					return err
+				}
 			}
 
 			var balances []models.LeaveBalance
@@ -687,7 +795,7 @@ func (h *LeaveHandler) UpdatePolicies(c *gin.Context) {
 				if !ok {
 					continue
 				}
-				total := proratedTotal(policy.Total, employee.HiredAt, balance.Year)
+				total := proratedTotal(policy.Total, employee.HiredAt, employee.TerminationDate, balance.Year)
 				if err := tx.Model(&models.LeaveBalance{}).
 					Where("id = ?", balance.ID).
 					Update("total", total).Error; err != nil {
@@ -712,7 +820,7 @@ func (h *LeaveHandler) ensureBalance(employeeID uuid.UUID, year int, leaveType s
 	var balance models.LeaveBalance
 	if err := h.DB.Where("employee_id = ? AND year = ? AND type = ?", employeeID, year, leaveType).
 		First(&balance).Error; err == nil {
-		policyTotal, err := h.getPolicyTotal(year, leaveType)
+		policyTotal, err := leavePolicyTotal(h.DB, year, leaveType)
 		if err != nil {
 			return balance, err
 		}
@@ -722,7 +830,7 @@ func (h *LeaveHandler) ensureBalance(employeeID uuid.UUID, year int, leaveType s
 			return balance, err
 		}
 
-		desiredTotal := proratedTotal(policyTotal, employee.HiredAt, year)
+		desiredTotal := proratedTotal(policyTotal, employee.HiredAt, employee.TerminationDate, year)
 		if desiredTotal != balance.Total {
 			if err := h.DB.Model(&models.LeaveBalance{}).
 				Where("id = ?", balance.ID).
@@ -736,7 +844,7 @@ func (h *LeaveHandler) ensureBalance(employeeID uuid.UUID, year int, leaveType s
 		return balance, err
 	}
 
-	defaultTotal, err := h.getPolicyTotal(year, leaveType)
+	defaultTotal, err := leavePolicyTotal(h.DB, year, leaveType)
 	if err != nil {
 		return balance, err
 	}
@@ -746,7 +854,7 @@ func (h *LeaveHandler) ensureBalance(employeeID uuid.UUID, year int, leaveType s
 		return balance, err
 	}
 
-	prorated := proratedTotal(defaultTotal, employee.HiredAt, year)
+	prorated := proratedTotal(defaultTotal, employee.HiredAt, employee.TerminationDate, year)
 
 	balance = models.LeaveBalance{
 		EmployeeID: employeeID,
@@ -763,37 +871,65 @@ func (h *LeaveHandler) ensureBalance(employeeID uuid.UUID, year int, leaveType s
 	return balance, nil
 }
 
+// leaveEmploymentError rejects leave for terminated employees and leave that
+// ends after a scheduled termination date.
+func leaveEmploymentError(db *gorm.DB, employeeID uuid.UUID, endDate time.Time) string {
+	var employee models.Employee
+	if err := db.First(&employee, "id = ?", employeeID).Error; err != nil {
+		return "employee not found"
+	}
+	if employee.Status == employeeStatusTerminated {
+		return "employee is terminated"
+	}
+	if employee.TerminationDate != nil && endDate.After(*employee.TerminationDate) {
+		return "leave extends past termination date"
+	}
+	return ""
+}
+
 func defaultLeaveTotals() map[string]float64 {
 	return map[string]float64{
 		"sick":   10,
 		"casual": 7,
+		"unpaid": 30,
 	}
 }
 
-func proratedTotal(policyTotal float64, hiredAt time.Time, year int) float64 {
-	if hiredAt.IsZero() {
-		return policyTotal
+// proratedTotal scales the yearly allowance to the whole months of year in
+// which the employee is employed, from the hire month through the exit month.
+func proratedTotal(policyTotal float64, hiredAt time.Time, exitAt *time.Time, year int) float64 {
+	firstMonth, lastMonth := 1, 12
+	if !hiredAt.IsZero() {
+		if hiredAt.Year() > year {
+			return 0
+		}
+		if hiredAt.Year() == year {
+			firstMonth = int(hiredAt.Month())
+		}
 	}
-	if hiredAt.Year() < year {
-		return policyTotal
+	if exitAt != nil {
+		if exitAt.Year() < year {
+			return 0
+		}
+		if exitAt.Year() == year {
+			lastMonth = int(exitAt.Month())
+		}
 	}
-	if hiredAt.Year() > year {
+
+	months := lastMonth - firstMonth + 1
+	if months <= 0 {
 		return 0
 	}
-
-	monthsRemaining := 12 - int(hiredAt.Month()) + 1
-	if monthsRemaining < 0 {
-		monthsRemaining = 0
-	} else if monthsRemaining > 12 {
-		monthsRemaining = 12
+	if months >= 12 {
+		return policyTotal
 	}
 
 	perMonth := policyTotal / 12
-	total := perMonth * float64(monthsRemaining)
+	total := perMonth * float64(months)
 	return math.Round(total*100) / 100
 }
 
-func (h *LeaveHandler) getPolicyTotal(year int, leaveType string) (float64, error) {
+func leavePolicyTotal(db *gorm.DB, year int, leaveType string) (float64, error) {
 	totals := defaultLeaveTotals()
 	defaultTotal, ok := totals[leaveType]
 	if !ok {
@@ -801,7 +937,7 @@ func (h *LeaveHandler) getPolicyTotal(year int, leaveType string) (float64, erro
 	}
 
 	var policy models.LeavePolicy
-	if err := h.DB.Where("year = ? AND type = ?", year, leaveType).First(&policy).Error; err != nil {
+	if err := db.Where("year = ? AND type = ?", year, leaveType).First(&policy).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return defaultTotal, nil
 		}
@@ -0,0 +1,291 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"erp-backend/internal/middleware"
+	"erp-backend/internal/models"
+)
+
+type orgChartNode struct {
+	ID             uuid.UUID       `json:"id"`
+	Name           string          `json:"name"`
+	Position       string          `json:"position"`
+	Role           string          `json:"role"`
+	Status         string          `json:"status"`
+	DepartmentID   *uuid.UUID      `json:"departmentId,omitempty"`
+	DepartmentName string          `json:"departmentName,omitempty"`
+	Children       []*orgChartNode `json:"children"`
+}
+
+// reportIDs returns the direct and indirect reports of managerID, walking
+// the reporting lines one level at a time. Cycles are ignored.
+func reportIDs(db *gorm.DB, managerID uuid.UUID) ([]uuid.UUID, error) {
+	seen := map[uuid.UUID]bool{managerID: true}
+	reports := []uuid.UUID{}
+	level := []uuid.UUID{managerID}
+	for len(level) > 0 {
+		var next []uuid.UUID
+		if err := db.Model(&models.Employee{}).Where("manager_id IN ?", level).Pluck("id", &next).Error; err != nil {
+			return nil, err
+		}
+		level = level[:0]
+		for _, id := range next {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			reports = append(reports, id)
+			level = append(level, id)
+		}
+	}
+	return reports, nil
+}
+
+// contextEmployeeID returns the employee linked to the logged in user.
+func contextEmployeeID(c *gin.Context) (uuid.UUID, bool) {
+	value, ok := c.Get(middleware.ContextEmployeeID)
+	if !ok || value == "" {
+		return uuid.Nil, false
+	}
+	id, err := uuid.Parse(value.(string))
+	if err != nil {
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// managerScope returns the employees a manager may see in listings. Managers
+// linked to an employee profile who have reports see those, direct and
+// indirect, and their own records. scoped is false for other roles and for
+// managers without a profile or without reports, who keep the role based
+// rule of seeing non-manager employees until reporting lines are set up.
+func managerScope(db *gorm.DB, c *gin.Context) (ids []uuid.UUID, scoped bool, err error) {
+	role, _ := c.Get(middleware.ContextRole)
+	if role != "manager" {
+		return nil, false, nil
+	}
+	managerID, ok := contextEmployeeID(c)
+	if !ok {
+		return nil, false, nil
+	}
+	ids, err = reportIDs(db, managerID)
+	if err != nil || len(ids) == 0 {
+		return nil, false, err
+	}
+	return append(ids, managerID), true, nil
+}
+
+// accessibleEmployee loads the employee named by the id parameter and applies
+// the rules of EmployeeHandler: employees may only read their own records,
+// and managers only reach the employees they can list and never other
+// managers. manage is set for changes, which employees may never make and
+// managers may not make to their own record.
+func accessibleEmployee(db *gorm.DB, c *gin.Context, manage bool) (models.Employee, int, string) {
+	employeeID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return models.Employee{}, http.StatusBadRequest, "invalid id"
+	}
+	return accessibleEmployeeByID(db, c, employeeID, manage)
+}
+
+// accessibleEmployeeByID is accessibleEmployee for an id taken from
+// elsewhere than the path, such as a request body.
+func accessibleEmployeeByID(db *gorm.DB, c *gin.Context, employeeID uuid.UUID, manage bool) (models.Employee, int, string) {
+	role, _ := c.Get(middleware.ContextRole)
+	if role == "employee" {
+		contextEmployeeID, ok := c.Get(middleware.ContextEmployeeID)
+		if manage || !ok || contextEmployeeID != employeeID.String() {
+			return models.Employee{}, http.StatusForbidden, "forbidden"
+		}
+	}
+
+	var employee models.Employee
+	if err := db.First(&employee, "id = ?", employeeID).Error; err != nil {
+		return models.Employee{}, http.StatusNotFound, "employee not found"
+	}
+	if role == "manager" {
+		if own, ok := contextEmployeeID(c); ok && own == employee.ID {
+			if manage {
+				return models.Employee{}, http.StatusForbidden, "manager cannot manage their own record"
+			}
+			return employee, 0, ""
+		}
+		if strings.EqualFold(employee.Role, "manager") {
+			return models.Employee{}, http.StatusForbidden, "manager cannot manage manager"
+		}
+		reports, scoped, err := managerScope(db, c)
+		if err != nil {
+			return models.Employee{}, http.StatusInternalServerError, "could not load employee"
+		}
+		if scoped && !containsUUID(reports, employee.ID) {
+			return models.Employee{}, http.StatusForbidden, "forbidden"
+		}
+	}
+	return employee, 0, ""
+}
+
+// employeeAccessError checks that the caller may record or change data of
+// employeeID: their own, or that of an employee they can manage.
+func employeeAccessError(db *gorm.DB, c *gin.Context, employeeID uuid.UUID) (int, string) {
+	if own, ok := contextEmployeeID(c); ok && own == employeeID {
+		return 0, ""
+	}
+	_, status, message := accessibleEmployeeByID(db, c, employeeID, true)
+	return status, message
+}
+
+// scopeEmployeeManager keeps an employee created or changed by a manager
+// with reports inside that manager's scope, where they can still reach it:
+// a missing manager defaults to the caller and managers outside the scope
+// are refused. Other callers are left alone.
+func scopeEmployeeManager(db *gorm.DB, c *gin.Context, employee *models.Employee) (int, string) {
+	reports, scoped, err := managerScope(db, c)
+	if err != nil {
+		return http.StatusInternalServerError, "could not load employee"
+	}
+	if !scoped {
+		return 0, ""
+	}
+	if employee.ManagerID == nil {
+		own, _ := contextEmployeeID(c)
+		employee.ManagerID = &own
+		return 0, ""
+	}
+	if !containsUUID(reports, *employee.ManagerID) {
+		return http.StatusForbidden, "manager must be yourself or one of your reports"
+	}
+	return 0, ""
+}
+
+func containsUUID(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, value := range ids {
+		if value == id {
+			return true
+		}
+	}
+	return false
+}
+
+// validateManager checks that managerID exists and that assigning it to
+// employeeID would not create a reporting cycle.
+func validateManager(db *gorm.DB, employeeID uuid.UUID, managerID uuid.UUID) string {
+	if managerID == employeeID {
+		return "employee cannot manage themselves"
+	}
+	current := managerID
+	for depth := 0; depth < 100; depth++ {
+		var manager models.Employee
+		if err := db.First(&manager, "id = ?", current).Error; err != nil {
+			if depth == 0 {
+				return "manager not found"
+			}
+			return ""
+		}
+		if manager.ManagerID == nil {
+			return ""
+		}
+		if *manager.ManagerID == employeeID {
+			return "manager assignment would create a cycle"
+		}
+		current = *manager.ManagerID
+	}
+	return "reporting chain too deep"
+}
+
+// OrgChart returns the reporting tree of current employees. rootId limits
+// the tree to one employee and their reports.
+func (h *EmployeeHandler) OrgChart(c *gin.Context) {
+	var employees []models.Employee
+	if err := h.DB.Where("status <> ?", employeeStatusTerminated).
+		Order("last_name asc, first_name asc").
+		Find(&employees).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load employees"})
+		return
+	}
+
+	var departments []models.Department
+	if err := h.DB.Find(&departments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load departments"})
+		return
+	}
+	departmentNames := make(map[uuid.UUID]string, len(departments))
+	for _, department := range departments {
+		departmentNames[department.ID] = department.Name
+	}
+
+	byID := make(map[uuid.UUID]models.Employee, len(employees))
+	for _, employee := range employees {
+		byID[employee.ID] = employee
+	}
+	children := map[uuid.UUID][]uuid.UUID{}
+	for _, employee := range employees {
+		if employee.ManagerID != nil {
+			if _, ok := byID[*employee.ManagerID]; ok {
+				children[*employee.ManagerID] = append(children[*employee.ManagerID], employee.ID)
+			}
+		}
+	}
+
+	nodes := make(map[uuid.UUID]*orgChartNode, len(employees))
+	var build func(id uuid.UUID) *orgChartNode
+	build = func(id uuid.UUID) *orgChartNode {
+		employee := byID[id]
+		node := &orgChartNode{
+			ID:           employee.ID,
+			Name:         employee.FirstName + " " + employee.LastName,
+			Position:     employee.Position,
+			Role:         employee.Role,
+			Status:       employee.Status,
+			DepartmentID: employee.DepartmentID,
+			Children:     []*orgChartNode{},
+		}
+		if employee.DepartmentID != nil {
+			node.DepartmentName = departmentNames[*employee.DepartmentID]
+		}
+		nodes[id] = node
+		for _, childID := range children[id] {
+			if _, visited := nodes[childID]; !visited {
+				node.Children = append(node.Children, build(childID))
+			}
+		}
+		return node
+	}
+
+	roots := []*orgChartNode{}
+	for _, employee := range employees {
+		if employee.ManagerID == nil {
+			roots = append(roots, build(employee.ID))
+		} else if _, ok := byID[*employee.ManagerID]; !ok {
+			roots = append(roots, build(employee.ID))
+		}
+	}
+	// Employees caught in a reporting cycle are unreachable from any root;
+	// list them at the top level rather than dropping them.
+	for _, employee := range employees {
+		if _, visited := nodes[employee.ID]; !visited {
+			roots = append(roots, build(employee.ID))
+		}
+	}
+
+	if value := c.Query("rootId"); value != "" {
+		rootID, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid rootId"})
+			return
+		}
+		node, ok := nodes[rootID]
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "employee not found"})
+			return
+		}
+		roots = []*orgChartNode{node}
+	}
+
+	c.JSON(http.StatusOK, roots)
+}
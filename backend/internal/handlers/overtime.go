@@ -0,0 +1,475 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"erp-backend/internal/middleware"
+	"erp-backend/internal/models"
+)
+
+const (
+	overtimeDailyHoursSettingKey        = "overtime_daily_hours"
+	overtimeWeeklyHoursSettingKey       = "overtime_weekly_hours"
+	overtimeMultiplierSettingKey        = "overtime_multiplier"
+	overtimeWeekendMultiplierSettingKey = "overtime_weekend_multiplier"
+	overtimeHolidayMultiplierSettingKey = "overtime_holiday_multiplier"
+	overtimeNightStartSettingKey        = "overtime_night_start"
+	overtimeNightEndSettingKey          = "overtime_night_end"
+	overtimeHolidaysSettingKey          = "overtime_holidays"
+
+	workDayRegular = "regular"
+	workDayWeekend = "weekend"
+	workDayHoliday = "holiday"
+)
+
+// overtimeRules configures how worked time is split. A zero threshold turns
+// that threshold off, and a night window with equal start and end turns night
+// hours off. Holidays are "2006-01-02" dates.
+type overtimeRules struct {
+	DailyHours        float64  `json:"dailyHours"`
+	WeeklyHours       float64  `json:"weeklyHours"`
+	Multiplier        float64  `json:"multiplier"`
+	WeekendMultiplier float64  `json:"weekendMultiplier"`
+	HolidayMultiplier float64  `json:"holidayMultiplier"`
+	NightStart        string   `json:"nightStart"`
+	NightEnd          string   `json:"nightEnd"`
+	Holidays          []string `json:"holidays"`
+}
+
+var defaultOvertimeRules = overtimeRules{
+	DailyHours:        8,
+	WeeklyHours:       40,
+	Multiplier:        1.5,
+	WeekendMultiplier: 1.5,
+	HolidayMultiplier: 2,
+	NightStart:        "22:00",
+	NightEnd:          "06:00",
+	Holidays:          []string{},
+}
+
+// workHours is worked time split by the overtime rules. Worked is the sum
+// of regular, overtime, weekend and holiday hours; night hours overlap them.
+type workHours struct {
+	Worked   float64 `json:"workedHours"`
+	Regular  float64 `json:"regularHours"`
+	Overtime float64 `json:"overtimeHours"`
+	Weekend  float64 `json:"weekendHours"`
+	Holiday  float64 `json:"holidayHours"`
+	Night    float64 `json:"nightHours"`
+}
+
+func (w *workHours) add(other workHours) {
+	w.Worked += other.Worked
+	w.Regular += other.Regular
+	w.Overtime += other.Overtime
+	w.Weekend += other.Weekend
+	w.Holiday += other.Holiday
+	w.Night += other.Night
+}
+
+func (w workHours) rounded() workHours {
+	return workHours{
+		Worked:   roundMoney(w.Worked),
+		Regular:  roundMoney(w.Regular),
+		Overtime: roundMoney(w.Overtime),
+		Weekend:  roundMoney(w.Weekend),
+		Holiday:  roundMoney(w.Holiday),
+		Night:    roundMoney(w.Night),
+	}
+}
+
+type workDay struct {
+	Date    string `json:"date"`
+	DayType string `json:"dayType"`
+	workHours
+}
+
+type workWeek struct {
+	WeekStart string `json:"weekStart"`
+	workHours
+}
+
+// workSummary is an employee's worked time over a date range. PayableHours
+// weighs overtime, weekend and holiday hours with their multipliers.
+type workSummary struct {
+	EmployeeID   uuid.UUID  `json:"employeeId"`
+	EmployeeName string     `json:"employeeName"`
+	Days         []workDay  `json:"days"`
+	Weeks        []workWeek `json:"weeks"`
+	Totals       workHours  `json:"totals"`
+	PayableHours float64    `json:"payableHours"`
+}
+
+// validate normalises the rules and returns a message for invalid ones.
+func (r *overtimeRules) validate() string {
+	if r.DailyHours < 0 || r.DailyHours > 24 {
+		return "dailyHours must be between 0 and 24"
+	}
+	if r.WeeklyHours < 0 || r.WeeklyHours > 168 {
+		return "weeklyHours must be between 0 and 168"
+	}
+	if r.Multiplier < 1 || r.WeekendMultiplier < 1 || r.HolidayMultiplier < 1 {
+		return "multipliers must be at least 1"
+	}
+	nightStart, ok := parseClock(r.NightStart)
+	if !ok {
+		return "nightStart must be HH:MM"
+	}
+	nightEnd, ok := parseClock(r.NightEnd)
+	if !ok {
+		return "nightEnd must be HH:MM"
+	}
+	r.NightStart = nightStart.Format("15:04")
+	r.NightEnd = nightEnd.Format("15:04")
+
+	seen := map[string]bool{}
+	holidays := []string{}
+	for _, value := range r.Holidays {
+		parsed, err := time.Parse("2006-01-02", strings.TrimSpace(value))
+		if err != nil {
+			return "invalid holiday " + value
+		}
+		date := parsed.Format("2006-01-02")
+		if !seen[date] {
+			seen[date] = true
+			holidays = append(holidays, date)
+		}
+	}
+	sort.Strings(holidays)
+	r.Holidays = holidays
+	return ""
+}
+
+func (r overtimeRules) settings() map[string]string {
+	return map[string]string{
+		overtimeDailyHoursSettingKey:        strconv.FormatFloat(r.DailyHours, 'f', -1, 64),
+		overtimeWeeklyHoursSettingKey:       strconv.FormatFloat(r.WeeklyHours, 'f', -1, 64),
+		overtimeMultiplierSettingKey:        strconv.FormatFloat(r.Multiplier, 'f', -1, 64),
+		overtimeWeekendMultiplierSettingKey: strconv.FormatFloat(r.WeekendMultiplier, 'f', -1, 64),
+		overtimeHolidayMultiplierSettingKey: strconv.FormatFloat(r.HolidayMultiplier, 'f', -1, 64),
+		overtimeNightStartSettingKey:        r.NightStart,
+		overtimeNightEndSettingKey:          r.NightEnd,
+		overtimeHolidaysSettingKey:          strings.Join(r.Holidays, ","),
+	}
+}
+
+// loadOvertimeRules reads the rules from settings, falling back to the
+// defaults for missing or invalid values.
+func loadOvertimeRules(db *gorm.DB) (overtimeRules, error) {
+	values, err := loadSettings(db,
+		overtimeDailyHoursSettingKey, overtimeWeeklyHoursSettingKey, overtimeMultiplierSettingKey,
+		overtimeWeekendMultiplierSettingKey, overtimeHolidayMultiplierSettingKey,
+		overtimeNightStartSettingKey, overtimeNightEndSettingKey, overtimeHolidaysSettingKey)
+	if err != nil {
+		return overtimeRules{}, err
+	}
+
+	rules := defaultOvertimeRules
+	floats := map[string]*float64{
+		overtimeDailyHoursSettingKey:        &rules.DailyHours,
+		overtimeWeeklyHoursSettingKey:       &rules.WeeklyHours,
+		overtimeMultiplierSettingKey:        &rules.Multiplier,
+		overtimeWeekendMultiplierSettingKey: &rules.WeekendMultiplier,
+		overtimeHolidayMultiplierSettingKey: &rules.HolidayMultiplier,
+	}
+	for key, target := range floats {
+		if parsed, err := strconv.ParseFloat(values[key], 64); err == nil {
+			*target = parsed
+		}
+	}
+	if value := values[overtimeNightStartSettingKey]; value != "" {
+		rules.NightStart = value
+	}
+	if value := values[overtimeNightEndSettingKey]; value != "" {
+		rules.NightEnd = value
+	}
+	rules.Holidays = []string{}
+	for _, value := range strings.Split(values[overtimeHolidaysSettingKey], ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			rules.Holidays = append(rules.Holidays, value)
+		}
+	}
+	if rules.validate() != "" {
+		return defaultOvertimeRules, nil
+	}
+	return rules, nil
+}
+
+type timeSpan struct {
+	start time.Time
+	end   time.Time
+}
+
+// workedSpans returns the parts of record between check-in and end that
+// are not covered by a break.
+func workedSpans(record models.Attendance, end time.Time) []timeSpan {
+	if !end.After(record.CheckIn) {
+		return nil
+	}
+	breaks := append([]models.AttendanceBreak(nil), record.Breaks...)
+	sort.Slice(breaks, func(i, j int) bool { return breaks[i].BreakStart.Before(breaks[j].BreakStart) })
+
+	spans := []timeSpan{}
+	cursor := record.CheckIn
+	for _, br := range breaks {
+		breakEnd := end
+		if br.BreakEnd != nil && br.BreakEnd.Before(end) {
+			breakEnd = *br.BreakEnd
+		}
+		if br.BreakStart.After(cursor) {
+			spanEnd := br.BreakStart
+			if spanEnd.After(end) {
+				spanEnd = end
+			}
+			if spanEnd.After(cursor) {
+				spans = append(spans, timeSpan{cursor, spanEnd})
+			}
+		}
+		if breakEnd.After(cursor) {
+			cursor = breakEnd
+		}
+	}
+	if end.After(cursor) {
+		spans = append(spans, timeSpan{cursor, end})
+	}
+	return spans
+}
+
+// nightDuration is the part of spans inside the nightly window of rules.
+func nightDuration(spans []timeSpan, rules overtimeRules) time.Duration {
+	if rules.NightStart == rules.NightEnd || len(spans) == 0 {
+		return 0
+	}
+	nightStart, _ := parseClock(rules.NightStart)
+	nightEnd, _ := parseClock(rules.NightEnd)
+
+	var total time.Duration
+	for _, span := range spans {
+		day := span.start.AddDate(0, 0, -1)
+		for !day.After(span.end) {
+			windowStart := time.Date(day.Year(), day.Month(), day.Day(), nightStart.Hour(), nightStart.Minute(), 0, 0, span.start.Location())
+			windowEnd := time.Date(day.Year(), day.Month(), day.Day(), nightEnd.Hour(), nightEnd.Minute(), 0, 0, span.start.Location())
+			if !windowEnd.After(windowStart) {
+				windowEnd = windowEnd.AddDate(0, 0, 1)
+			}
+			start, end := span.start, span.end
+			if windowStart.After(start) {
+				start = windowStart
+			}
+			if windowEnd.Before(end) {
+				end = windowEnd
+			}
+			if end.After(start) {
+				total += end.Sub(start)
+			}
+			day = day.AddDate(0, 0, 1)
+		}
+	}
+	return total
+}
+
+// summarizeAttendance splits the time an employee worked from from up to,
+// but excluding, to by the overtime rules. Records count on the local date
+// they were checked in. Weekend and holiday hours are paid as such and
+// count towards neither threshold; the weekly threshold applies to regular
+// hours left after the daily one, from Monday, so weeks cut by from are
+// read from their start. Open records count until now when includeOpen is
+// set and are skipped otherwise.
+func summarizeAttendance(db *gorm.DB, employeeID uuid.UUID, from, to time.Time, rules overtimeRules, now time.Time, includeOpen bool) (workSummary, error) {
+	summary := workSummary{EmployeeID: employeeID, Days: []workDay{}, Weeks: []workWeek{}}
+
+	first := dateOnly(from)
+	weekStart := mondayOf(first)
+	last := dateOnly(to)
+	localStart := time.Date(weekStart.Year(), weekStart.Month(), weekStart.Day(), 0, 0, 0, 0, time.Local)
+	localEnd := time.Date(last.Year(), last.Month(), last.Day(), 0, 0, 0, 0, time.Local)
+
+	query := db.Preload("Breaks").
+		Where("employee_id = ? AND check_in >= ? AND check_in < ?", employeeID, localStart, localEnd)
+	if !includeOpen {
+		query = query.Where("check_out IS NOT NULL")
+	}
+	var records []models.Attendance
+	if err := query.Order("check_in asc").Find(&records).Error; err != nil {
+		return summary, err
+	}
+
+	worked := map[string]time.Duration{}
+	night := map[string]time.Duration{}
+	for _, record := range records {
+		end := now
+		if record.CheckOut != nil {
+			end = *record.CheckOut
+		}
+		spans := workedSpans(record, end)
+		key := record.CheckIn.In(time.Local).Format("2006-01-02")
+		for _, span := range spans {
+			worked[key] += span.end.Sub(span.start)
+		}
+		night[key] += nightDuration(spans, rules)
+	}
+
+	holidays := map[string]bool{}
+	for _, holiday := range rules.Holidays {
+		holidays[holiday] = true
+	}
+	daily := time.Duration(rules.DailyHours * float64(time.Hour))
+	weekly := time.Duration(rules.WeeklyHours * float64(time.Hour))
+
+	var weekRegular time.Duration
+	var week *workWeek
+	for day := weekStart; day.Before(last); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() == time.Monday {
+			weekRegular = 0
+			week = nil
+		}
+		key := day.Format("2006-01-02")
+		entry := workDay{Date: key, DayType: workDayRegular}
+		total := worked[key]
+		switch {
+		case holidays[key]:
+			entry.DayType = workDayHoliday
+			entry.Holiday = total.Hours()
+		case day.Weekday() == time.Saturday || day.Weekday() == time.Sunday:
+			entry.DayType = workDayWeekend
+			entry.Weekend = total.Hours()
+		default:
+			regular, overtime := total, time.Duration(0)
+			if daily > 0 && regular > daily {
+				overtime = regular - daily
+				regular = daily
+			}
+			weekRegular += regular
+			if weekly > 0 && weekRegular > weekly {
+				excess := weekRegular - weekly
+				if excess > regular {
+					excess = regular
+				}
+				regular -= excess
+				overtime += excess
+				weekRegular = weekly
+			}
+			entry.Regular = regular.Hours()
+			entry.Overtime = overtime.Hours()
+		}
+		entry.Worked = total.Hours()
+		entry.Night = night[key].Hours()
+
+		if day.Before(first) || total == 0 {
+			continue
+		}
+		if week == nil {
+			summary.Weeks = append(summary.Weeks, workWeek{WeekStart: mondayOf(day).Format("2006-01-02")})
+			week = &summary.Weeks[len(summary.Weeks)-1]
+		}
+		week.add(entry.workHours)
+		summary.Totals.add(entry.workHours)
+		entry.workHours = entry.workHours.rounded()
+		summary.Days = append(summary.Days, entry)
+	}
+
+	for index := range summary.Weeks {
+		summary.Weeks[index].workHours = summary.Weeks[index].workHours.rounded()
+	}
+	totals := summary.Totals
+	summary.PayableHours = roundMoney(totals.Regular + totals.Overtime*rules.Multiplier +
+		totals.Weekend*rules.WeekendMultiplier + totals.Holiday*rules.HolidayMultiplier)
+	summary.Totals = totals.rounded()
+	return summary, nil
+}
+
+// Summary returns worked, overtime and night hours per day and week for the
+// employees in scope, for this month unless from and to are given.
+// Employees only get their own summary.
+func (h *AttendanceHandler) Summary(c *gin.Context) {
+	from, to, message := parseDateRange(c)
+	if message != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return
+	}
+	today := utcToday()
+	if from.IsZero() {
+		from = time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	if to.IsZero() {
+		to = time.Date(from.Year(), from.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	if to.Sub(from) > 366*24*time.Hour {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "date range is too long"})
+		return
+	}
+
+	employees := []models.Employee{}
+	role, _ := c.Get(middleware.ContextRole)
+	value := c.Query("employeeId")
+	if role == "employee" {
+		employeeID, ok := contextEmployeeID(c)
+		if !ok || (value != "" && value != employeeID.String()) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
+		value = employeeID.String()
+	}
+	if value != "" {
+		employeeID, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employeeId"})
+			return
+		}
+		employee, status, message := accessibleEmployeeByID(h.DB, c, employeeID, false)
+		if message != "" {
+			c.JSON(status, gin.H{"error": message})
+			return
+		}
+		employees = append(employees, employee)
+	} else {
+		query := h.DB.Where("hired_at < ? AND (termination_date IS NULL OR termination_date >= ?)",
+			to, from.Format("2006-01-02"))
+		reports, scoped, err := managerScope(h.DB, c)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load attendance summary"})
+			return
+		}
+		if scoped {
+			query = query.Where("id IN ?", reports)
+		} else if role == "manager" {
+			query = query.Where("role <> ?", "manager")
+		}
+		if err := query.Order("last_name asc, first_name asc").Find(&employees).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load attendance summary"})
+			return
+		}
+	}
+
+	rules, err := loadOvertimeRules(h.DB)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load overtime rules"})
+		return
+	}
+	now := time.Now()
+	summaries := make([]workSummary, 0, len(employees))
+	for _, employee := range employees {
+		summary, err := summarizeAttendance(h.DB, employee.ID, from, to, rules, now, true)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load attendance summary"})
+			return
+		}
+		summary.EmployeeName = employee.FirstName + " " + employee.LastName
+		summaries = append(summaries, summary)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"from":      from.Format("2006-01-02"),
+		"to":        to.AddDate(0, 0, -1).Format("2006-01-02"),
+		"rules":     rules,
+		"employees": summaries,
+	})
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 200
+)
+
+type listParams struct {
+	Page   int
+	Limit  int
+	Column string
+	Desc   bool
+}
+
+// parseListParams reads the shared page, limit, sort and order query
+// parameters. sortable maps the public field names accepted in sort to
+// their column; unknown names are rejected rather than interpolated.
+func parseListParams(c *gin.Context, sortable map[string]string, defaultSort string) (listParams, string) {
+	params := listParams{Page: 1, Limit: defaultPageLimit, Column: sortable[defaultSort], Desc: true}
+
+	if value := c.Query("page"); value != "" {
+		page, err := strconv.Atoi(value)
+		if err != nil || page < 1 {
+			return params, "invalid page"
+		}
+		params.Page = page
+	}
+	if value := c.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxPageLimit {
+			return params, "limit must be between 1 and " + strconv.Itoa(maxPageLimit)
+		}
+		params.Limit = limit
+	}
+	if value := c.Query("sort"); value != "" {
+		column, ok := sortable[value]
+		if !ok {
+			return params, "invalid sort"
+		}
+		params.Column = column
+	}
+	switch strings.ToLower(c.Query("order")) {
+	case "":
+	case "asc":
+		params.Desc = false
+	case "desc":
+		params.Desc = true
+	default:
+		return params, "invalid order"
+	}
+	return params, ""
+}
+
+// apply orders and pages query. The id tie-breaker keeps pages stable when
+// many rows share the same sort value.
+func (p listParams) apply(query *gorm.DB, table string) *gorm.DB {
+	direction := " desc"
+	if !p.Desc {
+		direction = " asc"
+	}
+	return query.
+		Order(table + "." + p.Column + direction).
+		Order(table + ".id" + direction).
+		Offset((p.Page - 1) * p.Limit).
+		Limit(p.Limit)
+}
+
+func pageResponse(items interface{}, total int64, params listParams) gin.H {
+	return gin.H{
+		"items": items,
+		"total": total,
+		"page":  params.Page,
+		"limit": params.Limit,
+	}
+}
+
+// parseDateRange reads inclusive from/to dates (YYYY-MM-DD) and returns the
+// half-open range [from, to+1d). Missing bounds are returned as zero times.
+func parseDateRange(c *gin.Context) (time.Time, time.Time, string) {
+	var from, to time.Time
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return from, to, "invalid from"
+		}
+		from = parsed
+	}
+	if value := c.Query("to"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			return from, to, "invalid to"
+		}
+		to = parsed.AddDate(0, 0, 1)
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return from, to, "from must not be after to"
+	}
+	return from, to, ""
+}
+
+// likeEscaper escapes the LIKE wildcards, using MySQL's default backslash
+// escape character.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// containsPattern returns a LIKE pattern matching value anywhere, with
+// wildcards in value matched literally.
+func containsPattern(value string) string {
+	return "%" + likeEscaper.Replace(value) + "%"
+}
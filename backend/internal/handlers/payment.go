@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"erp-backend/internal/middleware"
+	"erp-backend/internal/models"
+)
+
+type PaymentHandler struct {
+	DB *gorm.DB
+}
+
+type createPaymentRequest struct {
+	Amount    float64 `json:"amount" binding:"required"`
+	PaidAt    string  `json:"paidAt"`
+	Method    string  `json:"method"`
+	Reference string  `json:"reference"`
+}
+
+func NewPaymentHandler(db *gorm.DB) *PaymentHandler {
+	return &PaymentHandler{DB: db}
+}
+
+func normalizePaymentMethod(value string) (string, bool) {
+	method := strings.ToLower(strings.TrimSpace(value))
+	if method == "" {
+		return "bank_transfer", true
+	}
+	switch method {
+	case "bank_transfer", "cash", "card", "cheque", "other":
+		return method, true
+	}
+	return "", false
+}
+
+func (h *PaymentHandler) List(c *gin.Context) {
+	invoiceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var payments []models.Payment
+	if err := h.DB.Where("invoice_id = ?", invoiceID).Order("paid_at asc, created_at asc").Find(&payments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load payments"})
+		return
+	}
+	c.JSON(http.StatusOK, payments)
+}
+
+func (h *PaymentHandler) Create(c *gin.Context) {
+	invoiceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var req createPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	if req.Amount <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "amount must be positive"})
+		return
+	}
+
+	paidAt := time.Now()
+	if req.PaidAt != "" {
+		parsed, err := time.Parse("2006-01-02", req.PaidAt)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid paidAt"})
+			return
+		}
+		if parsed.After(time.Now()) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "paidAt cannot be in the future"})
+			return
+		}
+		paidAt = parsed
+	}
+
+	method, validMethod := normalizePaymentMethod(req.Method)
+	if !validMethod {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid method"})
+		return
+	}
+
+	payment := models.Payment{
+		InvoiceID: invoiceID,
+		Amount:    roundMoney(req.Amount),
+		PaidAt:    paidAt,
+		Method:    method,
+		Reference: strings.TrimSpace(req.Reference),
+	}
+	if userID, ok := c.Get(middleware.ContextUserID); ok {
+		if parsed, err := uuid.Parse(userID.(string)); err == nil {
+			payment.RecordedBy = &parsed
+		}
+	}
+
+	var invoice models.Invoice
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&invoice, "id = ?", invoiceID).Error; err != nil {
+			return err
+		}
+		if invoice.Status == invoiceStatusDraft || invoice.Status == invoiceStatusVoid {
+			return gorm.ErrInvalidTransaction
+		}
+		if payment.Amount > invoiceBalance(invoice)+0.005 {
+			return gorm.ErrInvalidData
+		}
+		if err := tx.Create(&payment).Error; err != nil {
+			return err
+		}
+		if err := recordAudit(tx, c, auditActionCreate, "payment", payment.ID, nil, payment); err != nil {
+			return err
+		}
+		return refreshInvoiceStatus(tx, &invoice)
+	}); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "invoice not found"})
+			return
+		}
+		if err == gorm.ErrInvalidTransaction {
+			c.JSON(http.StatusConflict, gin.H{"error": "invoice is not open for payment"})
+			return
+		}
+		if err == gorm.ErrInvalidData {
+			c.JSON(http.StatusConflict, gin.H{"error": "payment exceeds balance"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "payment failed"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"payment": payment,
+		"invoice": invoice,
+	})
+}
+
+func (h *PaymentHandler) Delete(c *gin.Context) {
+	invoiceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	paymentID, err := uuid.Parse(c.Param("paymentId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid paymentId"})
+		return
+	}
+
+	var invoice models.Invoice
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&invoice, "id = ?", invoiceID).Error; err != nil {
+			return err
+		}
+		var payment models.Payment
+		if err := tx.First(&payment, "id = ? AND invoice_id = ?", paymentID, invoiceID).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&payment).Error; err != nil {
+			return err
+		}
+		if err := recordAudit(tx, c, auditActionDelete, "payment", payment.ID, payment, nil); err != nil {
+			return err
+		}
+		return refreshInvoiceStatus(tx, &invoice)
+	}); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			c.JSON(http.StatusNotFound, gin.H{"error": "payment not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "delete failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, invoice)
+}
@@ -0,0 +1,696 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"erp-backend/internal/config"
+	"erp-backend/internal/middleware"
+	"erp-backend/internal/models"
+)
+
+type PayrollHandler struct {
+	DB  *gorm.DB
+	Cfg config.Config
+}
+
+const (
+	payrollStatusDraft     = "draft"
+	payrollStatusFinalized = "finalized"
+
+	payrollKindEarning   = "earning"
+	payrollKindAllowance = "allowance"
+	payrollKindDeduction = "deduction"
+
+	payrollCalculationFixed   = "fixed"
+	payrollCalculationPercent = "percent"
+)
+
+// unpaidLeaveTypes are the leave types deducted from pay.
+var unpaidLeaveTypes = []string{"unpaid"}
+
+var errPayrollPeriodLocked = errors.New("payroll period is locked")
+
+// errPayrollPeriodOpen rejects finalizing a run before its period has ended,
+// which would lock attendance that is still being recorded.
+var errPayrollPeriodOpen = errors.New("payroll period has not ended")
+
+type createPayrollRunRequest struct {
+	Period string `json:"period" binding:"required"`
+}
+
+type payrollComponentRequest struct {
+	Name        string  `json:"name" binding:"required"`
+	Kind        string  `json:"kind" binding:"required"`
+	Calculation string  `json:"calculation"`
+	Amount      float64 `json:"amount"`
+	EmployeeID  string  `json:"employeeId"`
+	Active      *bool   `json:"active"`
+}
+
+func NewPayrollHandler(db *gorm.DB, cfg config.Config) *PayrollHandler {
+	return &PayrollHandler{DB: db, Cfg: cfg}
+}
+
+// payrollPeriodLocked reports whether any day in [from, to] belongs to a
+// finalized payroll run.
+func payrollPeriodLocked(db *gorm.DB, from, to time.Time) (bool, error) {
+	var count int64
+	if err := db.Model(&models.PayrollRun{}).
+		Where("status = ? AND period_start <= ? AND period_end >= ?", payrollStatusFinalized, to.Format("2006-01-02"), from.Format("2006-01-02")).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// payrollPeriodLockedResponse rejects a change to data dated within a
+// finalized payroll period. It reports whether a response was written.
+func payrollPeriodLockedResponse(c *gin.Context, db *gorm.DB, from, to time.Time) bool {
+	locked, err := payrollPeriodLocked(db, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "payroll lock check failed"})
+		return true
+	}
+	if locked {
+		c.JSON(http.StatusConflict, gin.H{"error": errPayrollPeriodLocked.Error()})
+		return true
+	}
+	return false
+}
+
+// weekdays counts Monday to Friday dates in the inclusive range [from, to].
+func weekdays(from, to time.Time) float64 {
+	count := 0.0
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		if day.Weekday() != time.Saturday && day.Weekday() != time.Sunday {
+			count++
+		}
+	}
+	return count
+}
+
+func dateOnly(value time.Time) time.Time {
+	return time.Date(value.Year(), value.Month(), value.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// salaryOn returns the monthly salary in effect for employee on date, taken
+// from the compensation history with the stored salary as fallback.
+func salaryOn(db *gorm.DB, employee models.Employee, date time.Time) (float64, string, error) {
+	var change models.CompensationChange
+	err := db.Where("employee_id = ? AND effective_date <= ?", employee.ID, date.Format("2006-01-02")).
+		Order("effective_date desc, created_at desc").First(&change).Error
+	if err == nil {
+		return change.Amount, change.Currency, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return 0, "", err
+	}
+	return employee.Salary, employee.SalaryCurrency, nil
+}
+
+// buildPayslips computes a payslip for every employee employed during the
+// run's period. Base pay is the monthly salary prorated by the working days
+// (Monday to Friday) employed in the period, less approved unpaid leave.
+// Employees archived during or after the period are included; without a
+// termination date their employment ends on the day they were archived.
+func buildPayslips(db *gorm.DB, run models.PayrollRun) ([]models.Payslip, error) {
+	start, end := run.PeriodStart, run.PeriodEnd
+	periodDays := weekdays(start, end)
+	fallbackCurrency := baseCurrency(db)
+
+	var employees []models.Employee
+	if err := db.Unscoped().
+		Where("hired_at < ? AND (termination_date IS NULL OR termination_date >= ?)",
+			end.AddDate(0, 0, 1), start.Format("2006-01-02")).
+		Where("deleted_at IS NULL OR deleted_at >= ?", start).
+		Order("last_name asc, first_name asc").
+		Find(&employees).Error; err != nil {
+		return nil, err
+	}
+
+	// Overtime, weekend and holiday hours are paid on top of the salary at
+	// an hourly rate of the salary spread over the period's working hours.
+	rules, err := loadOvertimeRules(db)
+	if err != nil {
+		return nil, err
+	}
+	dailyHours := rules.DailyHours
+	if dailyHours == 0 {
+		dailyHours = defaultOvertimeRules.DailyHours
+	}
+
+	var components []models.PayrollComponent
+	if err := db.Where("active = ?", true).Order("kind asc, name asc").Find(&components).Error; err != nil {
+		return nil, err
+	}
+
+	payslips := make([]models.Payslip, 0, len(employees))
+	for _, employee := range employees {
+		from, to := start, end
+		if hired := dateOnly(employee.HiredAt); hired.After(from) {
+			from = hired
+		}
+		if employee.TerminationDate != nil && dateOnly(*employee.TerminationDate).Before(to) {
+			to = dateOnly(*employee.TerminationDate)
+		}
+		if employee.TerminationDate == nil && employee.DeletedAt.Valid && dateOnly(employee.DeletedAt.Time).Before(to) {
+			to = dateOnly(employee.DeletedAt.Time)
+		}
+		employedDays := weekdays(from, to)
+
+		salary, currency, err := salaryOn(db, employee, to)
+		if err != nil {
+			return nil, err
+		}
+		if currency == "" {
+			currency = fallbackCurrency
+		}
+
+		var leaves []models.LeaveRequest
+		if err := db.Where("employee_id = ? AND status = ? AND type IN ? AND start_date <= ? AND end_date >= ?",
+			employee.ID, "approved", unpaidLeaveTypes, to, from).
+			Find(&leaves).Error; err != nil {
+			return nil, err
+		}
+		unpaidDays := 0.0
+		for _, leave := range leaves {
+			leaveFrom, leaveTo := dateOnly(leave.StartDate), dateOnly(leave.EndDate)
+			if leaveFrom.Before(from) {
+				leaveFrom = from
+			}
+			if leaveTo.After(to) {
+				leaveTo = to
+			}
+			unpaidDays += weekdays(leaveFrom, leaveTo)
+		}
+		paidDays := employedDays - unpaidDays
+		if paidDays < 0 {
+			paidDays = 0
+		}
+
+		work, err := summarizeAttendance(db, employee.ID, from, to.AddDate(0, 0, 1), rules, time.Now(), false)
+		if err != nil {
+			return nil, err
+		}
+
+		basePay := 0.0
+		hourlyRate := 0.0
+		if periodDays > 0 {
+			basePay = roundMoney(salary * paidDays / periodDays)
+			hourlyRate = salary / (periodDays * dailyHours)
+		}
+		payslip := models.Payslip{
+			EmployeeID:      employee.ID,
+			EmployeeName:    employee.FirstName + " " + employee.LastName,
+			Position:        employee.Position,
+			Currency:        currency,
+			MonthlySalary:   salary,
+			WorkingDays:     periodDays,
+			PaidDays:        paidDays,
+			UnpaidLeaveDays: unpaidDays,
+			WorkedHours:     work.Totals.Worked,
+			OvertimeHours:   work.Totals.Overtime,
+			BasePay:         basePay,
+			Lines:           []models.PayslipLine{{Kind: payrollKindEarning, Name: "Base pay", Amount: basePay}},
+		}
+		// Holidays on weekdays are paid days already covered by the base
+		// pay, so working them only earns the part above the normal rate.
+		weekdayHoliday := 0.0
+		for _, day := range work.Days {
+			if day.DayType != workDayHoliday {
+				continue
+			}
+			date, err := time.Parse("2006-01-02", day.Date)
+			if err == nil && date.Weekday() != time.Saturday && date.Weekday() != time.Sunday {
+				weekdayHoliday += day.Holiday
+			}
+		}
+		holidayMultiplier := rules.HolidayMultiplier - 1
+		if holidayMultiplier < 0 {
+			holidayMultiplier = 0
+		}
+		premiums := []struct {
+			name         string
+			payableHours float64
+		}{
+			{"Overtime", work.Totals.Overtime * rules.Multiplier},
+			{"Weekend work", work.Totals.Weekend * rules.WeekendMultiplier},
+			{"Holiday work", weekdayHoliday*holidayMultiplier +
+				(work.Totals.Holiday-weekdayHoliday)*rules.HolidayMultiplier},
+		}
+		for _, premium := range premiums {
+			amount := roundMoney(hourlyRate * premium.payableHours)
+			if amount <= 0 {
+				continue
+			}
+			payslip.OvertimePay += amount
+			payslip.Lines = append(payslip.Lines, models.PayslipLine{Kind: payrollKindEarning, Name: premium.name, Amount: amount})
+		}
+		for _, component := range components {
+			if component.EmployeeID != nil && *component.EmployeeID != employee.ID {
+				continue
+			}
+			amount := component.Amount
+			if component.Calculation == payrollCalculationPercent {
+				amount = basePay * component.Amount / 100
+			}
+			amount = roundMoney(amount)
+			if component.Kind == payrollKindDeduction {
+				payslip.Deductions += amount
+			} else {
+				payslip.Allowances += amount
+			}
+			payslip.Lines = append(payslip.Lines, models.PayslipLine{Kind: component.Kind, Name: component.Name, Amount: amount})
+		}
+		for index := range payslip.Lines {
+			payslip.Lines[index].Position = index
+		}
+		payslip.Allowances = roundMoney(payslip.Allowances)
+		payslip.Deductions = roundMoney(payslip.Deductions)
+		payslip.OvertimePay = roundMoney(payslip.OvertimePay)
+		payslip.GrossPay = roundMoney(basePay + payslip.OvertimePay + payslip.Allowances)
+		payslip.NetPay = roundMoney(payslip.GrossPay - payslip.Deductions)
+		payslips = append(payslips, payslip)
+	}
+	return payslips, nil
+}
+
+func deletePayslips(tx *gorm.DB, runID uuid.UUID) error {
+	var payslipIDs []uuid.UUID
+	if err := tx.Model(&models.Payslip{}).Where("payroll_run_id = ?", runID).Pluck("id", &payslipIDs).Error; err != nil {
+		return err
+	}
+	if len(payslipIDs) == 0 {
+		return nil
+	}
+	if err := tx.Where("payslip_id IN ?", payslipIDs).Delete(&models.PayslipLine{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("id IN ?", payslipIDs).Delete(&models.Payslip{}).Error
+}
+
+// replacePayslips recomputes the payslips of a draft run.
+func replacePayslips(tx *gorm.DB, run *models.PayrollRun) error {
+	if err := deletePayslips(tx, run.ID); err != nil {
+		return err
+	}
+	payslips, err := buildPayslips(tx, *run)
+	if err != nil {
+		return err
+	}
+	for index := range payslips {
+		payslips[index].PayrollRunID = run.ID
+		if err := tx.Create(&payslips[index]).Error; err != nil {
+			return err
+		}
+	}
+	run.Payslips = payslips
+	return nil
+}
+
+func preloadPayslips(db *gorm.DB) *gorm.DB {
+	return db.Preload("Payslips", func(db *gorm.DB) *gorm.DB {
+		return db.Order("employee_name asc")
+	}).Preload("Payslips.Lines", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position asc")
+	})
+}
+
+// visiblePayslips drops the payslips a manager may not see from run: they
+// only see those of employees they can reach, which excludes managers and
+// admins. Payslips of archived employees are kept.
+func visiblePayslips(db *gorm.DB, c *gin.Context, run *models.PayrollRun) error {
+	role, _ := c.Get(middleware.ContextRole)
+	if role != "manager" {
+		return nil
+	}
+	query := db.Unscoped().Model(&models.Employee{}).Where("role = ?", "employee")
+	reports, scoped, err := managerScope(db, c)
+	if err != nil {
+		return err
+	}
+	if scoped {
+		query = query.Where("id IN ?", reports)
+	}
+	var ids []uuid.UUID
+	if err := query.Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+	payslips := make([]models.Payslip, 0, len(run.Payslips))
+	for _, payslip := range run.Payslips {
+		if containsUUID(ids, payslip.EmployeeID) {
+			payslips = append(payslips, payslip)
+		}
+	}
+	run.Payslips = payslips
+	return nil
+}
+
+func (h *PayrollHandler) ListRuns(c *gin.Context) {
+	query := h.DB.Model(&models.PayrollRun{})
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	runs := []models.PayrollRun{}
+	if err := query.Order("period desc").Find(&runs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load payroll runs"})
+		return
+	}
+	c.JSON(http.StatusOK, runs)
+}
+
+func (h *PayrollHandler) GetRun(c *gin.Context) {
+	runID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	var run models.PayrollRun
+	if err := preloadPayslips(h.DB).First(&run, "id = ?", runID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "payroll run not found"})
+		return
+	}
+	if err := visiblePayslips(h.DB, c, &run); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load payroll run"})
+		return
+	}
+	c.JSON(http.StatusOK, run)
+}
+
+// CreateRun generates a draft run for a calendar month given as YYYY-MM.
+func (h *PayrollHandler) CreateRun(c *gin.Context) {
+	var req createPayrollRunRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	periodStart, err := time.Parse("2006-01", strings.TrimSpace(req.Period))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "period must be YYYY-MM"})
+		return
+	}
+	if periodStart.After(utcToday()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "period cannot be in the future"})
+		return
+	}
+
+	run := models.PayrollRun{
+		Period:      periodStart.Format("2006-01"),
+		PeriodStart: periodStart,
+		PeriodEnd:   periodStart.AddDate(0, 1, -1),
+		Status:      payrollStatusDraft,
+		CreatedBy:   actorUserID(c),
+	}
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		var existing models.PayrollRun
+		if err := tx.Where("period = ?", run.Period).First(&existing).Error; err == nil {
+			return gorm.ErrDuplicatedKey
+		} else if err != gorm.ErrRecordNotFound {
+			return err
+		}
+		if err := tx.Create(&run).Error; err != nil {
+			return err
+		}
+		if err := replacePayslips(tx, &run); err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionCreate, "payroll_run", run.ID, nil, run)
+	}); err != nil {
+		if err == gorm.ErrDuplicatedKey {
+			c.JSON(http.StatusConflict, gin.H{"error": "payroll run for period already exists"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "payroll run failed"})
+		return
+	}
+	if err := visiblePayslips(h.DB, c, &run); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load payroll run"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, run)
+}
+
+// lockDraftRun loads the run for update and rejects finalized runs.
+func lockDraftRun(tx *gorm.DB, runID uuid.UUID, run *models.PayrollRun) error {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(run, "id = ?", runID).Error; err != nil {
+		return err
+	}
+	if run.Status != payrollStatusDraft {
+		return errPayrollPeriodLocked
+	}
+	return nil
+}
+
+func payrollRunError(c *gin.Context, err error, fallback string) {
+	switch err {
+	case gorm.ErrRecordNotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": "payroll run not found"})
+	case errPayrollPeriodLocked:
+		c.JSON(http.StatusConflict, gin.H{"error": "payroll run is finalized"})
+	case errPayrollPeriodOpen:
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
+
+// Recalculate rebuilds the payslips of a draft run from the current data.
+func (h *PayrollHandler) Recalculate(c *gin.Context) {
+	runID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var run models.PayrollRun
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockDraftRun(tx, runID, &run); err != nil {
+			return err
+		}
+		if err := replacePayslips(tx, &run); err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionUpdate, "payroll_run", run.ID, nil, gin.H{"recalculated": true, "payslips": len(run.Payslips)})
+	}); err != nil {
+		payrollRunError(c, err, "recalculation failed")
+		return
+	}
+	if err := visiblePayslips(h.DB, c, &run); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load payroll run"})
+		return
+	}
+
+	c.JSON(http.StatusOK, run)
+}
+
+// Finalize locks a draft run once its period has ended. Its payslips can no
+// longer change, and attendance and leave inside the period are locked
+// against edits.
+func (h *PayrollHandler) Finalize(c *gin.Context) {
+	runID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var run models.PayrollRun
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := lockDraftRun(tx, runID, &run); err != nil {
+			return err
+		}
+		if !dateOnly(run.PeriodEnd).Before(utcToday()) {
+			return errPayrollPeriodOpen
+		}
+		before := run
+		now := time.Now()
+		run.Status = payrollStatusFinalized
+		run.FinalizedAt = &now
+		run.FinalizedBy = actorUserID(c)
+		if err := tx.Save(&run).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionUpdate, "payroll_run", run.ID, before, run)
+	}); err != nil {
+		payrollRunError(c, err, "finalize failed")
+		return
+	}
+
+	if err := preloadPayslips(h.DB).First(&run, "id = ?", run.ID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load payroll run"})
+		return
+	}
+	c.JSON(http.StatusOK, run)
+}
+
+func (h *PayrollHandler) DeleteRun(c *gin.Context) {
+	runID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		var run models.PayrollRun
+		if err := lockDraftRun(tx, runID, &run); err != nil {
+			return err
+		}
+		if err := deletePayslips(tx, run.ID); err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.PayrollRun{}, "id = ?", run.ID).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionDelete, "payroll_run", run.ID, run, nil)
+	}); err != nil {
+		payrollRunError(c, err, "delete failed")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
+
+func (h *PayrollHandler) ListComponents(c *gin.Context) {
+	components := []models.PayrollComponent{}
+	if err := h.DB.Order("kind asc, name asc").Find(&components).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load payroll components"})
+		return
+	}
+	c.JSON(http.StatusOK, components)
+}
+
+// applyComponentRequest validates req into component.
+func applyComponentRequest(db *gorm.DB, component *models.PayrollComponent, req payrollComponentRequest) string {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return "name required"
+	}
+	kind := strings.ToLower(strings.TrimSpace(req.Kind))
+	if kind != payrollKindAllowance && kind != payrollKindDeduction {
+		return "kind must be allowance or deduction"
+	}
+	calculation := strings.ToLower(strings.TrimSpace(req.Calculation))
+	if calculation == "" {
+		calculation = payrollCalculationFixed
+	}
+	if calculation != payrollCalculationFixed && calculation != payrollCalculationPercent {
+		return "calculation must be fixed or percent"
+	}
+	if req.Amount < 0 || (calculation == payrollCalculationPercent && req.Amount > 100) {
+		return "invalid amount"
+	}
+
+	component.Name = name
+	component.Kind = kind
+	component.Calculation = calculation
+	component.Amount = req.Amount
+	component.EmployeeID = nil
+	if req.EmployeeID != "" {
+		employeeID, err := uuid.Parse(req.EmployeeID)
+		if err != nil {
+			return "invalid employeeId"
+		}
+		var employee models.Employee
+		if err := db.First(&employee, "id = ?", employeeID).Error; err != nil {
+			return "employee not found"
+		}
+		component.EmployeeID = &employeeID
+	}
+	if req.Active != nil {
+		component.Active = *req.Active
+	}
+	return ""
+}
+
+func (h *PayrollHandler) CreateComponent(c *gin.Context) {
+	var req payrollComponentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+
+	component := models.PayrollComponent{Active: true}
+	if message := applyComponentRequest(h.DB, &component, req); message != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return
+	}
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&component).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionCreate, "payroll_component", component.ID, nil, component)
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "create failed"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, component)
+}
+
+func (h *PayrollHandler) UpdateComponent(c *gin.Context) {
+	var req payrollComponentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	componentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var component models.PayrollComponent
+	if err := h.DB.First(&component, "id = ?", componentID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "payroll component not found"})
+		return
+	}
+	before := component
+	if message := applyComponentRequest(h.DB, &component, req); message != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return
+	}
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&component).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionUpdate, "payroll_component", component.ID, before, component)
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, component)
+}
+
+func (h *PayrollHandler) DeleteComponent(c *gin.Context) {
+	componentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var component models.PayrollComponent
+	if err := h.DB.First(&component, "id = ?", componentID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "payroll component not found"})
+		return
+	}
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.PayrollComponent{}, "id = ?", componentID).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionDelete, "payroll_component", component.ID, component, nil)
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "delete failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
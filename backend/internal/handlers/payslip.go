@@ -0,0 +1,267 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"erp-backend/internal/email"
+	"erp-backend/internal/models"
+)
+
+func sendPayslipPDF(c *gin.Context, db *gorm.DB, run models.PayrollRun, payslip models.Payslip) {
+	company, err := loadCompanyProfile(db)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load company"})
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="`+payslipFilename(run, payslip)+`"`)
+	c.Data(http.StatusOK, "application/pdf", renderPayslipPDF(run, payslip, company))
+}
+
+// PayslipPDF renders any payslip of a run, including drafts, for payroll
+// staff to review.
+func (h *PayrollHandler) PayslipPDF(c *gin.Context) {
+	runID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	payslipID, err := uuid.Parse(c.Param("payslipId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payslipId"})
+		return
+	}
+
+	var run models.PayrollRun
+	if err := h.DB.First(&run, "id = ?", runID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "payroll run not found"})
+		return
+	}
+	var payslip models.Payslip
+	if err := h.DB.Preload("Lines", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position asc")
+	}).First(&payslip, "id = ? AND payroll_run_id = ?", payslipID, run.ID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "payslip not found"})
+		return
+	}
+	run.Payslips = []models.Payslip{payslip}
+	if err := visiblePayslips(h.DB, c, &run); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load payslip"})
+		return
+	}
+	if len(run.Payslips) == 0 {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+
+	sendPayslipPDF(c, h.DB, run, payslip)
+}
+
+// MyPayslips lists the caller's payslips from finalized runs, newest first.
+func (h *PayrollHandler) MyPayslips(c *gin.Context) {
+	employeeID, ok := contextEmployeeID(c)
+	if !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+
+	type myPayslip struct {
+		models.Payslip
+		Period      string    `json:"period"`
+		PeriodStart time.Time `json:"periodStart"`
+		PeriodEnd   time.Time `json:"periodEnd"`
+	}
+	var runs []models.PayrollRun
+	if err := h.DB.Where("status = ?", payrollStatusFinalized).Order("period desc").Find(&runs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load payslips"})
+		return
+	}
+	runIDs := make([]uuid.UUID, 0, len(runs))
+	for _, run := range runs {
+		runIDs = append(runIDs, run.ID)
+	}
+	payslips := []models.Payslip{}
+	if len(runIDs) > 0 {
+		if err := h.DB.Preload("Lines", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position asc")
+		}).Where("employee_id = ? AND payroll_run_id IN ?", employeeID, runIDs).Find(&payslips).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load payslips"})
+			return
+		}
+	}
+	byRun := make(map[uuid.UUID]models.Payslip, len(payslips))
+	for _, payslip := range payslips {
+		byRun[payslip.PayrollRunID] = payslip
+	}
+
+	items := []myPayslip{}
+	for _, run := range runs {
+		if payslip, ok := byRun[run.ID]; ok {
+			items = append(items, myPayslip{Payslip: payslip, Period: run.Period, PeriodStart: run.PeriodStart, PeriodEnd: run.PeriodEnd})
+		}
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// MyPayslipPDF downloads one of the caller's finalized payslips. Payslips of
+// other employees and of draft runs are reported as not found.
+func (h *PayrollHandler) MyPayslipPDF(c *gin.Context) {
+	employeeID, ok := contextEmployeeID(c)
+	if !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+	payslipID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var payslip models.Payslip
+	if err := h.DB.Preload("Lines", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position asc")
+	}).First(&payslip, "id = ? AND employee_id = ?", payslipID, employeeID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "payslip not found"})
+		return
+	}
+	var run models.PayrollRun
+	if err := h.DB.First(&run, "id = ? AND status = ?", payslip.PayrollRunID, payrollStatusFinalized).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "payslip not found"})
+		return
+	}
+	run.Payslips = []models.Payslip{payslip}
+	if err := visiblePayslips(h.DB, c, &run); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load payslip"})
+		return
+	}
+	if len(run.Payslips) == 0 {
+		c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+		return
+	}
+
+	sendPayslipPDF(c, h.DB, run, payslip)
+}
+
+// EmailPayslips queues every payslip of a finalized run to be emailed to the
+// employee with the PDF attached; RunPayslipEmails does the sending.
+// Payslips already emailed are skipped unless resend=true.
+func (h *PayrollHandler) EmailPayslips(c *gin.Context) {
+	runID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+	resend := c.Query("resend") == "true"
+
+	var run models.PayrollRun
+	if err := h.DB.First(&run, "id = ?", runID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "payroll run not found"})
+		return
+	}
+	if run.Status != payrollStatusFinalized {
+		c.JSON(http.StatusConflict, gin.H{"error": "payroll run is not finalized"})
+		return
+	}
+
+	query := h.DB.Model(&models.Payslip{}).Where("payroll_run_id = ?", run.ID)
+	if !resend {
+		query = query.Where("emailed_at IS NULL")
+	}
+	result := query.Updates(map[string]any{"email_queued_at": time.Now(), "email_error": ""})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not queue payslips"})
+		return
+	}
+	var total int64
+	if err := h.DB.Model(&models.Payslip{}).Where("payroll_run_id = ?", run.ID).Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not queue payslips"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"queued": result.RowsAffected, "skipped": total - result.RowsAffected})
+}
+
+// RunPayslipEmails sends the queued payslips. Each payslip is taken off the
+// queue before it is sent, so concurrent runs never send it twice; failures
+// are kept on the payslip as EmailError and can be queued again.
+func (h *PayrollHandler) RunPayslipEmails(now time.Time) error {
+	var payslips []models.Payslip
+	if err := h.DB.Preload("Lines", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position asc")
+	}).
+		Where("email_queued_at IS NOT NULL").
+		Order("email_queued_at asc").
+		Find(&payslips).Error; err != nil {
+		return err
+	}
+	if len(payslips) == 0 {
+		return nil
+	}
+	company, err := loadCompanyProfile(h.DB)
+	if err != nil {
+		return err
+	}
+
+	runs := map[uuid.UUID]models.PayrollRun{}
+	for _, payslip := range payslips {
+		claim := h.DB.Model(&models.Payslip{}).
+			Where("id = ? AND email_queued_at IS NOT NULL", payslip.ID).
+			Update("email_queued_at", nil)
+		if claim.Error != nil {
+			return claim.Error
+		}
+		if claim.RowsAffected == 0 {
+			continue
+		}
+
+		run, ok := runs[payslip.PayrollRunID]
+		if !ok {
+			if err := h.DB.First(&run, "id = ?", payslip.PayrollRunID).Error; err != nil {
+				return err
+			}
+			runs[run.ID] = run
+		}
+
+		failure := ""
+		var employee models.Employee
+		if err := h.DB.Unscoped().First(&employee, "id = ?", payslip.EmployeeID).Error; err != nil || employee.Email == "" {
+			failure = "no email address"
+		} else if err := h.sendPayslip(run, payslip, employee, company); err != nil {
+			log.Printf("payslip %s email failed: %v", payslip.ID, err)
+			failure = "email failed"
+		}
+		updates := map[string]any{"email_error": failure}
+		if failure == "" {
+			updates["emailed_at"] = now
+		}
+		if err := h.DB.Model(&models.Payslip{}).Where("id = ?", payslip.ID).Updates(updates).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (h *PayrollHandler) sendPayslip(run models.PayrollRun, payslip models.Payslip, employee models.Employee, company companyProfile) error {
+	sender := company.Name
+	if sender == "" {
+		sender = "WorkFlow ERP"
+	}
+
+	subject := "Your payslip for " + run.PeriodStart.Format("January 2006")
+	body := "Dear " + employee.FirstName + ",\n\n" +
+		"Please find attached your payslip for " + run.PeriodStart.Format("January 2006") + ". " +
+		"Net pay: " + formatMoney(payslip.NetPay) + " " + payslip.Currency + ".\n" +
+		"You can also download your payslips from WorkFlow ERP at any time.\n\n" +
+		"Kind regards,\n" + sender
+	attachment := email.Attachment{
+		Filename:    payslipFilename(run, payslip),
+		ContentType: "application/pdf",
+		Data:        renderPayslipPDF(run, payslip, company),
+	}
+	return email.SendMessage(smtpConfig(h.Cfg), employee.Email, subject, body, attachment)
+}
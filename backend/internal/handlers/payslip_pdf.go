@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"fmt"
+
+	"erp-backend/internal/models"
+	"erp-backend/internal/pdf"
+)
+
+func payslipFilename(run models.PayrollRun, payslip models.Payslip) string {
+	return "payslip-" + run.Period + "-" + payslip.EmployeeID.String()[:8] + ".pdf"
+}
+
+func renderPayslipPDF(run models.PayrollRun, payslip models.Payslip, company companyProfile) []byte {
+	doc := pdf.New()
+	right := pdf.PageWidth - pdfMargin
+
+	y := drawCompanyHeader(doc, company)
+	doc.Text(pdfMargin, y, 22, true, "PAYSLIP")
+	y += 26
+
+	meta := [][2]string{
+		{"Employee", payslip.EmployeeName},
+		{"Position", payslip.Position},
+		{"Pay period", run.PeriodStart.Format("2006-01-02") + " to " + run.PeriodEnd.Format("2006-01-02")},
+		{"Currency", payslip.Currency},
+	}
+	for _, row := range meta {
+		if row[1] == "" {
+			continue
+		}
+		doc.Text(pdfMargin, y, 10, true, row[0])
+		doc.Text(pdfMargin+100, y, 10, false, row[1])
+		y += 14
+	}
+	y += 10
+
+	attendance := [][2]string{
+		{"Monthly salary", formatMoney(payslip.MonthlySalary)},
+		{"Working days", fmt.Sprintf("%g", payslip.WorkingDays)},
+		{"Paid days", fmt.Sprintf("%g", payslip.PaidDays)},
+		{"Unpaid leave days", fmt.Sprintf("%g", payslip.UnpaidLeaveDays)},
+		{"Hours worked", fmt.Sprintf("%.2f", payslip.WorkedHours)},
+		{"Overtime hours", fmt.Sprintf("%.2f", payslip.OvertimeHours)},
+	}
+	for _, row := range attendance {
+		doc.Text(pdfMargin, y, 9, false, row[0])
+		doc.TextRight(pdfMargin+220, y, 9, false, row[1])
+		y += 12
+	}
+	y += 16
+
+	drawSection := func(title string, kinds ...string) {
+		doc.FillRect(pdfMargin, y-12, right-pdfMargin, 18, 0.9)
+		doc.Text(pdfMargin+4, y, 9, true, title)
+		doc.TextRight(right-4, y, 9, true, "Amount")
+		y += 20
+		for _, line := range payslip.Lines {
+			matches := false
+			for _, kind := range kinds {
+				matches = matches || line.Kind == kind
+			}
+			if !matches {
+				continue
+			}
+			if y > pdfBottomEdge {
+				doc.AddPage()
+				y = pdfMargin + 12
+			}
+			doc.Text(pdfMargin+4, y, 9, false, pdf.Truncate(line.Name, 380, 9, false))
+			doc.TextRight(right-4, y, 9, false, formatMoney(line.Amount))
+			y += 16
+		}
+		y += 8
+	}
+	drawSection("Earnings", payrollKindEarning, payrollKindAllowance)
+	drawSection("Deductions", payrollKindDeduction)
+
+	if y > pdfBottomEdge-60 {
+		doc.AddPage()
+		y = pdfMargin + 12
+	}
+	doc.Line(pdfMargin, y-8, right, y-8, 0.5)
+	y += 8
+
+	totals := []struct {
+		label string
+		value float64
+		bold  bool
+	}{
+		{"Gross pay", payslip.GrossPay, false},
+		{"Deductions", payslip.Deductions, false},
+		{"Net pay", payslip.NetPay, true},
+	}
+	for _, total := range totals {
+		doc.TextRight(440, y, 10, total.bold, total.label)
+		doc.TextRight(right-4, y, 10, total.bold, formatMoney(total.value)+" "+payslip.Currency)
+		y += 15
+	}
+
+	return doc.Bytes()
+}
@@ -0,0 +1,458 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"erp-backend/internal/config"
+	"erp-backend/internal/email"
+	"erp-backend/internal/models"
+)
+
+type RecurringInvoiceHandler struct {
+	DB  *gorm.DB
+	Cfg config.Config
+}
+
+type createRecurringInvoiceRequest struct {
+	CustomerID    string               `json:"customerId" binding:"required"`
+	Currency      string               `json:"currency"`
+	Interval      string               `json:"interval" binding:"required"`
+	StartAt       string               `json:"startAt" binding:"required"`
+	EndAt         string               `json:"endAt"`
+	Status        string               `json:"status"`
+	AutoSend      bool                 `json:"autoSend"`
+	EmailCustomer bool                 `json:"emailCustomer"`
+	Lines         []invoiceLineRequest `json:"lines" binding:"required"`
+}
+
+const (
+	recurringStatusActive = "active"
+	recurringStatusPaused = "paused"
+	recurringStatusEnded  = "ended"
+
+	// recurringCatchUpLimit caps how many missed periods a single template
+	// generates in one scheduler run, e.g. after a long outage.
+	recurringCatchUpLimit = 12
+)
+
+func NewRecurringInvoiceHandler(db *gorm.DB, cfg config.Config) *RecurringInvoiceHandler {
+	return &RecurringInvoiceHandler{DB: db, Cfg: cfg}
+}
+
+func normalizeRecurringInterval(value string) (string, bool) {
+	interval := strings.ToLower(strings.TrimSpace(value))
+	switch interval {
+	case "weekly", "monthly", "quarterly", "yearly":
+		return interval, true
+	}
+	return "", false
+}
+
+// addMonthsClamped adds months to t, clamping the day to the end of the
+// target month so a schedule anchored on the 31st stays at month end.
+func addMonthsClamped(t time.Time, months int) time.Time {
+	year, month, day := t.Date()
+	first := time.Date(year, month+time.Month(months), 1, 0, 0, 0, 0, t.Location())
+	lastDay := first.AddDate(0, 1, -1).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(first.Year(), first.Month(), day, 0, 0, 0, 0, t.Location())
+}
+
+// recurringRunDate returns the date of the occurrence with the given index,
+// always computed from the start date so month-end clamping does not drift.
+func recurringRunDate(start time.Time, interval string, occurrence int) time.Time {
+	switch interval {
+	case "weekly":
+		return start.AddDate(0, 0, 7*occurrence)
+	case "quarterly":
+		return addMonthsClamped(start, 3*occurrence)
+	case "yearly":
+		return addMonthsClamped(start, 12*occurrence)
+	default:
+		return addMonthsClamped(start, occurrence)
+	}
+}
+
+func recurringLinesFromRequest(items []invoiceLineRequest) ([]models.RecurringInvoiceLine, string) {
+	if len(items) == 0 {
+		return nil, "lines required"
+	}
+	if _, message := buildInvoiceLines(createInvoiceRequest{Lines: items}); message != "" {
+		return nil, message
+	}
+	lines := make([]models.RecurringInvoiceLine, 0, len(items))
+	for index, item := range items {
+		lines = append(lines, models.RecurringInvoiceLine{
+			Position:    index + 1,
+			Description: strings.TrimSpace(item.Description),
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice,
+			Discount:    item.Discount,
+			TaxRate:     item.TaxRate,
+		})
+	}
+	return lines, ""
+}
+
+// applyRecurringRequest validates req and copies it onto template, returning a
+// client-facing error message when the input is rejected.
+func (h *RecurringInvoiceHandler) applyRecurringRequest(template *models.RecurringInvoice, req createRecurringInvoiceRequest) ([]models.RecurringInvoiceLine, string) {
+	customerID, err := uuid.Parse(req.CustomerID)
+	if err != nil {
+		return nil, "invalid customerId"
+	}
+	var customer models.Customer
+	if err := h.DB.First(&customer, "id = ?", customerID).Error; err != nil {
+		return nil, "customer not found"
+	}
+
+	interval, ok := normalizeRecurringInterval(req.Interval)
+	if !ok {
+		return nil, "invalid interval"
+	}
+	startAt, err := time.Parse("2006-01-02", req.StartAt)
+	if err != nil {
+		return nil, "invalid startAt"
+	}
+	var endAt *time.Time
+	if req.EndAt != "" {
+		parsed, err := time.Parse("2006-01-02", req.EndAt)
+		if err != nil {
+			return nil, "invalid endAt"
+		}
+		if parsed.Before(startAt) {
+			return nil, "endAt must be after startAt"
+		}
+		endAt = &parsed
+	}
+
+	currency, ok := resolveInvoiceCurrency(req.Currency, customer)
+	if !ok {
+		return nil, "invalid currency"
+	}
+
+	status := strings.ToLower(strings.TrimSpace(req.Status))
+	if status == "" {
+		status = recurringStatusActive
+	}
+	if status != recurringStatusActive && status != recurringStatusPaused {
+		return nil, "invalid status"
+	}
+
+	lines, message := recurringLinesFromRequest(req.Lines)
+	if message != "" {
+		return nil, message
+	}
+
+	template.CustomerID = customer.ID
+	template.Currency = currency
+	template.Interval = interval
+	template.StartAt = startAt
+	template.EndAt = endAt
+	template.Status = status
+	template.AutoSend = req.AutoSend
+	template.EmailCustomer = req.EmailCustomer
+	return lines, ""
+}
+
+// scheduleNextRun positions NextRunAt on the next occurrence. New, resumed
+// or rescheduled templates skip occurrences that are already in the past
+// instead of back-filling them.
+func scheduleNextRun(template *models.RecurringInvoice, today time.Time) {
+	template.NextRunAt = recurringRunDate(template.StartAt, template.Interval, template.RunCount)
+	for template.NextRunAt.Before(today) {
+		template.RunCount++
+		template.NextRunAt = recurringRunDate(template.StartAt, template.Interval, template.RunCount)
+	}
+	if template.EndAt != nil && template.NextRunAt.After(*template.EndAt) {
+		template.Status = recurringStatusEnded
+	}
+}
+
+func utcToday() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func (h *RecurringInvoiceHandler) List(c *gin.Context) {
+	query := h.DB.Preload("Lines", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position asc")
+	})
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var templates []models.RecurringInvoice
+	if err := query.Order("next_run_at asc").Find(&templates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load recurring invoices"})
+		return
+	}
+	c.JSON(http.StatusOK, templates)
+}
+
+func (h *RecurringInvoiceHandler) Create(c *gin.Context) {
+	var req createRecurringInvoiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+
+	var template models.RecurringInvoice
+	lines, message := h.applyRecurringRequest(&template, req)
+	if message != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return
+	}
+	scheduleNextRun(&template, utcToday())
+	template.Lines = lines
+
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&template).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionCreate, "recurring_invoice", template.ID, nil, template)
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "create failed"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+func (h *RecurringInvoiceHandler) Update(c *gin.Context) {
+	var req createRecurringInvoiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+
+	templateID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	// The template is locked like in RunDue so a run in progress cannot
+	// overwrite the schedule, or the schedule a run just advanced.
+	var (
+		template models.RecurringInvoice
+		status   int
+		message  string
+	)
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Preload("Lines").
+			First(&template, "id = ?", templateID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				status, message = http.StatusNotFound, "recurring invoice not found"
+			}
+			return err
+		}
+		before := template
+
+		var lines []models.RecurringInvoiceLine
+		lines, message = h.applyRecurringRequest(&template, req)
+		if message != "" {
+			status = http.StatusBadRequest
+			return errors.New(message)
+		}
+		// Occurrences are counted from StartAt, so a new anchor restarts
+		// the count instead of carrying the old runs over to it.
+		if !template.StartAt.Equal(before.StartAt) || template.Interval != before.Interval {
+			template.RunCount = 0
+		}
+		scheduleNextRun(&template, utcToday())
+
+		if err := tx.Where("recurring_invoice_id = ?", template.ID).Delete(&models.RecurringInvoiceLine{}).Error; err != nil {
+			return err
+		}
+		for index := range lines {
+			lines[index].RecurringInvoiceID = template.ID
+		}
+		if err := tx.Create(&lines).Error; err != nil {
+			return err
+		}
+		if err := tx.Omit("Lines").Save(&template).Error; err != nil {
+			return err
+		}
+		template.Lines = lines
+		return recordAudit(tx, c, auditActionUpdate, "recurring_invoice", template.ID, before, template)
+	}); err != nil {
+		if message == "" {
+			status, message = http.StatusInternalServerError, "update failed"
+		}
+		c.JSON(status, gin.H{"error": message})
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+func (h *RecurringInvoiceHandler) Delete(c *gin.Context) {
+	templateID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var template models.RecurringInvoice
+	if err := h.DB.Preload("Lines").First(&template, "id = ?", templateID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "recurring invoice not found"})
+		return
+	}
+
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.RecurringInvoice{}, "id = ?", templateID).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionDelete, "recurring_invoice", template.ID, template, nil)
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "delete failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
+
+// RunDue generates invoices for every active template whose next run date has
+// arrived. It is safe to run from several server instances at once because
+// each occurrence is generated while holding a lock on its template.
+func (h *RecurringInvoiceHandler) RunDue(now time.Time) error {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	var templateIDs []uuid.UUID
+	if err := h.DB.Model(&models.RecurringInvoice{}).
+		Where("status = ? AND next_run_at <= ?", recurringStatusActive, today.Format("2006-01-02")).
+		Pluck("id", &templateIDs).Error; err != nil {
+		return err
+	}
+
+	for _, templateID := range templateIDs {
+		for occurrence := 0; occurrence < recurringCatchUpLimit; occurrence++ {
+			invoice, template, err := h.runTemplate(templateID, today)
+			if err != nil {
+				log.Printf("recurring invoice %s failed: %v", templateID, err)
+				_ = h.DB.Model(&models.RecurringInvoice{}).
+					Where("id = ?", templateID).
+					Update("last_error", err.Error()).Error
+				break
+			}
+			if invoice == nil {
+				break
+			}
+			// Drafts still need review, so only auto-sent invoices are
+			// emailed, including catch-up ones that are already overdue.
+			if template.EmailCustomer && template.AutoSend {
+				h.emailInvoice(*invoice)
+			}
+		}
+	}
+	return nil
+}
+
+// runTemplate generates a single due occurrence of the template. It returns a
+// nil invoice when the template is no longer due.
+func (h *RecurringInvoiceHandler) runTemplate(templateID uuid.UUID, today time.Time) (*models.Invoice, models.RecurringInvoice, error) {
+	var template models.RecurringInvoice
+	var invoice *models.Invoice
+
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Preload("Lines", func(db *gorm.DB) *gorm.DB {
+				return db.Order("position asc")
+			}).
+			First(&template, "id = ?", templateID).Error; err != nil {
+			return err
+		}
+		if template.Status != recurringStatusActive || template.NextRunAt.Format("2006-01-02") > today.Format("2006-01-02") {
+			return nil
+		}
+
+		status := invoiceStatusDraft
+		if template.AutoSend {
+			status = invoiceStatusSent
+		}
+		req := createInvoiceRequest{
+			CustomerID: template.CustomerID.String(),
+			Currency:   template.Currency,
+			Status:     status,
+			IssuedAt:   template.NextRunAt.Format("2006-01-02"),
+		}
+		for _, line := range template.Lines {
+			req.Lines = append(req.Lines, invoiceLineRequest{
+				Description: line.Description,
+				Quantity:    line.Quantity,
+				UnitPrice:   line.UnitPrice,
+				Discount:    line.Discount,
+				TaxRate:     line.TaxRate,
+			})
+		}
+
+		created, _, message := createInvoice(tx, req)
+		if message != "" {
+			return errors.New(message)
+		}
+		if err := recordAudit(tx, nil, auditActionCreate, "invoice", created.ID, nil, created); err != nil {
+			return err
+		}
+
+		runAt := time.Now()
+		template.RunCount++
+		template.LastInvoiceID = &created.ID
+		template.LastRunAt = &runAt
+		template.LastError = ""
+		template.NextRunAt = recurringRunDate(template.StartAt, template.Interval, template.RunCount)
+		if template.EndAt != nil && template.NextRunAt.After(*template.EndAt) {
+			template.Status = recurringStatusEnded
+		}
+		if err := tx.Omit("Lines").Save(&template).Error; err != nil {
+			return err
+		}
+		invoice = &created
+		return nil
+	})
+	return invoice, template, err
+}
+
+func (h *RecurringInvoiceHandler) emailInvoice(invoice models.Invoice) {
+	if invoice.CustomerID == nil {
+		return
+	}
+	var customer models.Customer
+	if err := h.DB.First(&customer, "id = ?", *invoice.CustomerID).Error; err != nil || customer.BillingEmail == "" {
+		return
+	}
+	company, err := loadCompanyProfile(h.DB)
+	if err != nil {
+		log.Printf("recurring invoice %s email skipped: %v", invoice.Number, err)
+		return
+	}
+
+	sender := company.Name
+	if sender == "" {
+		sender = "WorkFlow ERP"
+	}
+	subject := "Invoice " + invoice.Number + " from " + sender
+	body := "Dear " + customer.Name + ",\n\nPlease find attached invoice " + invoice.Number +
+		" for " + formatMoney(invoice.Amount) + " " + invoice.Currency +
+		", due on " + invoice.DueAt.Format("2006-01-02") + ".\n\nKind regards,\n" + sender
+	attachment := email.Attachment{
+		Filename:    "invoice-" + invoice.Number + ".pdf",
+		ContentType: "application/pdf",
+		Data:        renderInvoicePDF(invoice, customer, company),
+	}
+	if err := email.SendMessage(smtpConfig(h.Cfg), customer.BillingEmail, subject, body, attachment); err != nil {
+		log.Printf("recurring invoice %s email failed: %v", invoice.Number, err)
+	}
+}
@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"erp-backend/internal/models"
+)
+
+type ReportHandler struct {
+	DB *gorm.DB
+}
+
+func NewReportHandler(db *gorm.DB) *ReportHandler {
+	return &ReportHandler{DB: db}
+}
+
+type agingBuckets struct {
+	Current    float64 `json:"current"`
+	Days1To30  float64 `json:"days1To30"`
+	Days31To60 float64 `json:"days31To60"`
+	Days61To90 float64 `json:"days61To90"`
+	Days90Plus float64 `json:"days90Plus"`
+	Total      float64 `json:"total"`
+}
+
+func (b *agingBuckets) add(amount float64, overdue int) {
+	switch {
+	case overdue <= 0:
+		b.Current += amount
+	case overdue <= 30:
+		b.Days1To30 += amount
+	case overdue <= 60:
+		b.Days31To60 += amount
+	case overdue <= 90:
+		b.Days61To90 += amount
+	default:
+		b.Days90Plus += amount
+	}
+	b.Total += amount
+}
+
+func (b *agingBuckets) round() {
+	b.Current = roundMoney(b.Current)
+	b.Days1To30 = roundMoney(b.Days1To30)
+	b.Days31To60 = roundMoney(b.Days31To60)
+	b.Days61To90 = roundMoney(b.Days61To90)
+	b.Days90Plus = roundMoney(b.Days90Plus)
+	b.Total = roundMoney(b.Total)
+}
+
+type customerAging struct {
+	CustomerID   *uuid.UUID `json:"customerId"`
+	CustomerName string     `json:"customerName"`
+	Invoices     int        `json:"invoices"`
+	agingBuckets
+}
+
+type invoiceSumRow struct {
+	InvoiceID uuid.UUID
+	Total     float64
+}
+
+func sumByInvoice(db *gorm.DB, model interface{}, dateColumn string, before time.Time) (map[uuid.UUID]float64, error) {
+	var rows []invoiceSumRow
+	if err := db.Model(model).
+		Select("invoice_id, SUM(amount) AS total").
+		Where(dateColumn+" < ?", before).
+		Group("invoice_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	sums := make(map[uuid.UUID]float64, len(rows))
+	for _, row := range rows {
+		sums[row.InvoiceID] = row.Total
+	}
+	return sums, nil
+}
+
+// ReceivablesAging buckets the balance outstanding on each issued invoice as
+// of the requested date by the number of days past its due date. Payments and
+// credit notes dated after the as-of date are ignored, so past reports can be
+// reproduced. Balances are converted into the base currency at the as-of date.
+func (h *ReportHandler) ReceivablesAging(c *gin.Context) {
+	asOf := utcToday()
+	if value := c.Query("asOf"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid asOf"})
+			return
+		}
+		asOf = parsed
+	}
+	cutoff := asOf.AddDate(0, 0, 1)
+
+	var invoices []models.Invoice
+	if err := h.DB.
+		Where("status <> ? AND issued_at < ?", invoiceStatusDraft, cutoff).
+		Where("status <> ? OR voided_at >= ?", invoiceStatusVoid, cutoff).
+		Find(&invoices).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load invoices"})
+		return
+	}
+
+	paid, err := sumByInvoice(h.DB, &models.Payment{}, "paid_at", cutoff)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load payments"})
+		return
+	}
+	credited, err := sumByInvoice(h.DB, &models.CreditNote{}, "issued_at", cutoff)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load credit notes"})
+		return
+	}
+
+	converter := newCurrencyConverter(h.DB)
+	missing := []string{}
+	seenMissing := map[string]bool{}
+	byCustomer := map[string]*customerAging{}
+	totals := agingBuckets{}
+
+	for _, invoice := range invoices {
+		balance := roundMoney(invoice.Amount - paid[invoice.ID] - credited[invoice.ID])
+		if balance <= 0 {
+			continue
+		}
+		converted, err := converter.Convert(balance, invoice.Currency, asOf)
+		if err != nil {
+			if !seenMissing[invoice.Currency] {
+				seenMissing[invoice.Currency] = true
+				missing = append(missing, invoice.Currency)
+			}
+			continue
+		}
+
+		key := invoice.CustomerName
+		if invoice.CustomerID != nil {
+			key = invoice.CustomerID.String()
+		}
+		row, ok := byCustomer[key]
+		if !ok {
+			row = &customerAging{CustomerID: invoice.CustomerID, CustomerName: invoice.CustomerName}
+			byCustomer[key] = row
+		}
+		overdue := daysOverdue(invoice.DueAt, asOf)
+		row.add(converted, overdue)
+		row.Invoices++
+		totals.add(converted, overdue)
+	}
+
+	customers := make([]customerAging, 0, len(byCustomer))
+	for _, row := range byCustomer {
+		row.round()
+		customers = append(customers, *row)
+	}
+	sort.Slice(customers, func(i, j int) bool {
+		return customers[i].CustomerName < customers[j].CustomerName
+	})
+	totals.round()
+	currency := baseCurrency(h.DB)
+
+	if c.Query("format") == "csv" {
+		writeAgingCSV(c, asOf, currency, customers, totals, missing)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"asOf":         asOf.Format("2006-01-02"),
+		"currency":     currency,
+		"customers":    customers,
+		"totals":       totals,
+		"missingRates": missing,
+	})
+}
+
+// writeAgingCSV writes the aging report as CSV. Invoices in currencies
+// without a usable rate are left out of the totals, so a trailing note lists
+// those currencies.
+func writeAgingCSV(c *gin.Context, asOf time.Time, currency string, customers []customerAging, totals agingBuckets, missing []string) {
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="receivables-aging-`+asOf.Format("2006-01-02")+`.csv"`)
+	c.Status(http.StatusOK)
+
+	money := func(value float64) string {
+		return strconv.FormatFloat(value, 'f', 2, 64)
+	}
+	record := func(name string, invoices string, b agingBuckets) []string {
+		return []string{name, invoices, money(b.Current), money(b.Days1To30), money(b.Days31To60),
+			money(b.Days61To90), money(b.Days90Plus), money(b.Total)}
+	}
+
+	w := csv.NewWriter(c.Writer)
+	_ = w.Write([]string{"Customer", "Invoices", "Current", "1-30", "31-60", "61-90", "90+", "Total (" + currency + ")"})
+	for _, row := range customers {
+		_ = w.Write(record(escapeCSVCell(row.CustomerName), strconv.Itoa(row.Invoices), row.agingBuckets))
+	}
+	_ = w.Write(record("Total", "", totals))
+	if len(missing) > 0 {
+		_ = w.Write([]string{"Excluded, no exchange rate: " + strings.Join(missing, ", ")})
+	}
+	w.Flush()
+}
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"erp-backend/internal/middleware"
+	"erp-backend/internal/models"
+)
+
+type SearchHandler struct {
+	DB *gorm.DB
+}
+
+type searchResult struct {
+	Type     string    `json:"type"`
+	ID       uuid.UUID `json:"id"`
+	Title    string    `json:"title"`
+	Subtitle string    `json:"subtitle"`
+	Score    int       `json:"score"`
+}
+
+const (
+	searchMinQueryLength = 2
+	searchCandidateLimit = 25
+	searchDefaultLimit   = 20
+)
+
+func NewSearchHandler(db *gorm.DB) *SearchHandler {
+	return &SearchHandler{DB: db}
+}
+
+// matchScore ranks how well value matches the lower-cased query: exact
+// matches beat prefix matches, which beat word-prefix and substring matches.
+// weight lets callers favour primary fields such as names and numbers.
+func matchScore(value, query string, weight int) int {
+	value = strings.ToLower(strings.TrimSpace(value))
+	switch {
+	case value == "":
+		return 0
+	case value == query:
+		return 100 * weight
+	case strings.HasPrefix(value, query):
+		return 60 * weight
+	case strings.Contains(value, " "+query):
+		return 40 * weight
+	case strings.Contains(value, query):
+		return 20 * weight
+	}
+	return 0
+}
+
+// searchRank orders candidates in SQL by their best match over columns, the
+// same tiers matchScore uses, so the candidate limit keeps the strongest
+// matches. then breaks ties.
+func searchRank(query string, then string, columns ...string) clause.OrderBy {
+	escaped := likeEscaper.Replace(query)
+	cases := make([]string, 0, len(columns))
+	vars := []any{}
+	for _, column := range columns {
+		cases = append(cases, "CASE WHEN "+column+" = ? THEN 0 WHEN "+column+" LIKE ? THEN 1 WHEN "+column+" LIKE ? THEN 2 ELSE 3 END")
+		vars = append(vars, query, escaped+"%", "% "+escaped+"%")
+	}
+	rank := cases[0]
+	if len(cases) > 1 {
+		rank = "LEAST(" + strings.Join(cases, ", ") + ")"
+	}
+	return clause.OrderBy{Expression: clause.Expr{SQL: rank + ", " + then, Vars: vars, WithoutParentheses: true}}
+}
+
+func bestScore(scores ...int) int {
+	best := 0
+	for _, score := range scores {
+		if score > best {
+			best = score
+		}
+	}
+	return best
+}
+
+func (h *SearchHandler) Search(c *gin.Context) {
+	query := strings.ToLower(strings.TrimSpace(c.Query("q")))
+	if len(query) < searchMinQueryLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q must be at least 2 characters"})
+		return
+	}
+	limit := searchDefaultLimit
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxPageLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+		limit = parsed
+	}
+
+	role, _ := c.Get(middleware.ContextRole)
+	employees := h.DB.Model(&models.Employee{})
+	if role == "employee" {
+		employeeID, ok := c.Get(middleware.ContextEmployeeID)
+		if !ok || employeeID == "" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
+		id, err := uuid.Parse(employeeID.(string))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employeeId"})
+			return
+		}
+		employees = employees.Where("id = ?", id)
+	} else if reports, scoped, err := managerScope(h.DB, c); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "search failed"})
+		return
+	} else if scoped {
+		employees = employees.Where("id IN ?", reports)
+	} else if role == "manager" {
+		employees = employees.Where("role = ?", "employee")
+	}
+
+	like := containsPattern(query)
+	results := []searchResult{}
+
+	var employeeMatches []models.Employee
+	if err := employees.
+		Where("first_name LIKE ? OR last_name LIKE ? OR CONCAT(first_name, ' ', last_name) LIKE ? OR email LIKE ? OR position LIKE ?",
+			like, like, like, like, like).
+		Clauses(searchRank(query, "last_name, first_name", "CONCAT(first_name, ' ', last_name)", "first_name", "last_name", "email")).
+		Limit(searchCandidateLimit).
+		Find(&employeeMatches).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "search failed"})
+		return
+	}
+	for _, employee := range employeeMatches {
+		name := employee.FirstName + " " + employee.LastName
+		results = append(results, searchResult{
+			Type:     "employee",
+			ID:       employee.ID,
+			Title:    name,
+			Subtitle: strings.TrimSpace(employee.Position + " " + employee.Email),
+			Score: bestScore(
+				matchScore(name, query, 3),
+				matchScore(employee.FirstName, query, 3),
+				matchScore(employee.LastName, query, 3),
+				matchScore(employee.Email, query, 2),
+				matchScore(employee.Position, query, 1),
+			),
+		})
+	}
+
+	if role == "admin" || role == "manager" {
+		var customers []models.Customer
+		if err := h.DB.
+			Where("name LIKE ? OR billing_email LIKE ?", like, like).
+			Clauses(searchRank(query, "name", "name", "billing_email")).
+			Limit(searchCandidateLimit).
+			Find(&customers).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "search failed"})
+			return
+		}
+		for _, customer := range customers {
+			results = append(results, searchResult{
+				Type:     "customer",
+				ID:       customer.ID,
+				Title:    customer.Name,
+				Subtitle: customer.BillingEmail,
+				Score: bestScore(
+					matchScore(customer.Name, query, 3),
+					matchScore(customer.BillingEmail, query, 2),
+				),
+			})
+		}
+
+		var invoices []models.Invoice
+		if err := h.DB.
+			Where("number LIKE ? OR customer_name LIKE ?", like, like).
+			Clauses(searchRank(query, "issued_at DESC", "number", "customer_name")).
+			Limit(searchCandidateLimit).
+			Find(&invoices).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "search failed"})
+			return
+		}
+		for _, invoice := range invoices {
+			results = append(results, searchResult{
+				Type:     "invoice",
+				ID:       invoice.ID,
+				Title:    invoice.Number,
+				Subtitle: invoice.CustomerName + " · " + formatMoney(invoice.Amount) + " " + invoice.Currency + " · " + invoice.Status,
+				Score: bestScore(
+					matchScore(invoice.Number, query, 3),
+					matchScore(invoice.CustomerName, query, 2),
+				),
+			})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Title < results[j].Title
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	c.JSON(http.StatusOK, gin.H{"query": query, "results": results})
+}
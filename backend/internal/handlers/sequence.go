@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"erp-backend/internal/models"
+)
+
+const (
+	invoiceSequenceKey    = "invoice"
+	creditNoteSequenceKey = "credit_note"
+)
+
+func formatSequenceNumber(sequence models.NumberSequence, value int64) string {
+	parts := []string{}
+	if sequence.Prefix != "" {
+		parts = append(parts, sequence.Prefix)
+	}
+	if sequence.YearlyReset {
+		parts = append(parts, fmt.Sprintf("%d", sequence.Year))
+	}
+	parts = append(parts, fmt.Sprintf("%0*d", sequence.Padding, value))
+	return strings.Join(parts, "-")
+}
+
+// allocateSequenceNumber reserves the next number of the sequence identified
+// by key. It must run inside the transaction that stores the numbered
+// document: the sequence row stays locked until commit, and a rollback
+// releases the number again, so numbers are neither duplicated nor skipped.
+// exists reports whether a candidate is already taken by a manually numbered
+// document, in which case the sequence moves past it.
+//
+// Yearly reset sequences number each year separately. The sequence row holds
+// the latest year; a document dated in a later year moves it on, and one
+// dated in an earlier year continues that year's counter.
+func allocateSequenceNumber(tx *gorm.DB, key string, at time.Time, exists func(string) (bool, error)) (string, error) {
+	var sequence models.NumberSequence
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&sequence, "`key` = ?", key).Error; err != nil {
+		return "", err
+	}
+
+	if !sequence.YearlyReset || sequence.Year == at.Year() {
+		number, err := nextSequenceNumber(sequence, &sequence.NextValue, exists)
+		if err != nil {
+			return "", err
+		}
+		return number, tx.Save(&sequence).Error
+	}
+
+	counter, err := sequenceYear(tx, key, at.Year())
+	if err != nil {
+		return "", err
+	}
+	if at.Year() < sequence.Year {
+		dated := sequence
+		dated.Year = at.Year()
+		number, err := nextSequenceNumber(dated, &counter.NextValue, exists)
+		if err != nil {
+			return "", err
+		}
+		return number, tx.Save(&counter).Error
+	}
+
+	// A later year: keep the current year's counter for backdated documents
+	// and continue from any numbers already issued in the new year.
+	previous := models.NumberSequenceYear{Key: key, Year: sequence.Year, NextValue: sequence.NextValue}
+	if err := tx.Save(&previous).Error; err != nil {
+		return "", err
+	}
+	sequence.Year = at.Year()
+	sequence.NextValue = counter.NextValue
+	number, err := nextSequenceNumber(sequence, &sequence.NextValue, exists)
+	if err != nil {
+		return "", err
+	}
+	if err := tx.Delete(&counter).Error; err != nil {
+		return "", err
+	}
+	return number, tx.Save(&sequence).Error
+}
+
+// sequenceYear loads the stored counter of key for year, starting at one when
+// the year has none yet.
+func sequenceYear(tx *gorm.DB, key string, year int) (models.NumberSequenceYear, error) {
+	counter := models.NumberSequenceYear{Key: key, Year: year, NextValue: 1}
+	err := tx.First(&counter, "`key` = ? AND year = ?", key, year).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return counter, err
+	}
+	return counter, nil
+}
+
+// nextSequenceNumber formats the number at *next and advances it, skipping
+// numbers that are already taken.
+func nextSequenceNumber(sequence models.NumberSequence, next *int64, exists func(string) (bool, error)) (string, error) {
+	if *next < 1 {
+		*next = 1
+	}
+	for {
+		number := formatSequenceNumber(sequence, *next)
+		*next++
+		taken, err := exists(number)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return number, nil
+		}
+	}
+}
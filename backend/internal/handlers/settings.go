@@ -3,9 +3,11 @@ package handlers
 import (
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"erp-backend/internal/models"
 )
@@ -20,29 +22,98 @@ type updateLogoRequest struct {
 	CollapsedLogoURL string `json:"collapsedLogoUrl"`
 }
 
+type updateCompanyRequest struct {
+	Name    string `json:"name" binding:"required"`
+	Address string `json:"address"`
+	Email   string `json:"email"`
+	Phone   string `json:"phone"`
+	TaxID   string `json:"taxId"`
+}
+
+type updateCurrencyRequest struct {
+	BaseCurrency string `json:"baseCurrency" binding:"required"`
+}
+
+type updateRemindersRequest struct {
+	Offsets []int `json:"offsets"`
+}
+
+type updateNumberSequenceRequest struct {
+	Prefix      string `json:"prefix"`
+	YearlyReset bool   `json:"yearlyReset"`
+	Padding     int    `json:"padding" binding:"required"`
+	NextValue   *int64 `json:"nextValue"`
+}
+
 const (
 	logoSettingKey          = "company_logo"
 	expandedLogoSettingKey  = "company_logo_expanded"
 	collapsedLogoSettingKey = "company_logo_collapsed"
+	companyNameSettingKey   = "company_name"
+	companyAddrSettingKey   = "company_address"
+	companyEmailSettingKey  = "company_email"
+	companyPhoneSettingKey  = "company_phone"
+	companyTaxIDSettingKey  = "company_tax_id"
 )
 
+func loadSettings(db *gorm.DB, keys ...string) (map[string]string, error) {
+	var settings []models.Setting
+	if err := db.Where("`key` IN ?", keys).Find(&settings).Error; err != nil {
+		return nil, err
+	}
+	values := map[string]string{}
+	for _, setting := range settings {
+		values[setting.Key] = strings.TrimSpace(setting.Value)
+	}
+	return values, nil
+}
+
+func saveSetting(db *gorm.DB, key string, value string) error {
+	var setting models.Setting
+	err := db.Where("`key` = ?", key).Take(&setting).Error
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			setting = models.Setting{Key: key, Value: value}
+			return db.Create(&setting).Error
+		}
+		return err
+	}
+	setting.Value = value
+	return db.Save(&setting).Error
+}
+
+// saveSettings stores a group of settings in one transaction and records a
+// single audit event for the group.
+func saveSettings(db *gorm.DB, c *gin.Context, group string, updates map[string]string) error {
+	keys := make([]string, 0, len(updates))
+	for key := range updates {
+		keys = append(keys, key)
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		before, err := loadSettings(tx, keys...)
+		if err != nil {
+			return err
+		}
+		for key, value := range updates {
+			if err := saveSetting(tx, key, value); err != nil {
+				return err
+			}
+		}
+		return recordAudit(tx, c, auditActionUpdate, "settings", group, before, updates)
+	})
+}
+
 func NewSettingsHandler(db *gorm.DB) *SettingsHandler {
 	return &SettingsHandler{DB: db}
 }
 
 func (h *SettingsHandler) GetLogo(c *gin.Context) {
-	keys := []string{expandedLogoSettingKey, collapsedLogoSettingKey, logoSettingKey}
-	var settings []models.Setting
-	if err := h.DB.Where("`key` IN ?", keys).Find(&settings).Error; err != nil {
+	values, err := loadSettings(h.DB, expandedLogoSettingKey, collapsedLogoSettingKey, logoSettingKey)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load logo"})
 		return
 	}
 
-	values := map[string]string{}
-	for _, setting := range settings {
-		values[setting.Key] = strings.TrimSpace(setting.Value)
-	}
-
 	expandedLogoURL := values[expandedLogoSettingKey]
 	collapsedLogoURL := values[collapsedLogoSettingKey]
 	legacyLogoURL := values[logoSettingKey]
@@ -86,27 +157,9 @@ func (h *SettingsHandler) UpdateLogo(c *gin.Context) {
 		collapsedLogoSettingKey: collapsedValue,
 	}
 
-	for key, value := range updates {
-		var setting models.Setting
-		err := h.DB.Where("`key` = ?", key).Take(&setting).Error
-		if err != nil {
-			if err == gorm.ErrRecordNotFound {
-				setting = models.Setting{Key: key, Value: value}
-				if createErr := h.DB.Create(&setting).Error; createErr != nil {
-					c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed"})
-					return
-				}
-				continue
-			}
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed"})
-			return
-		}
-
-		setting.Value = value
-		if err := h.DB.Save(&setting).Error; err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed"})
-			return
-		}
+	if err := saveSettings(h.DB, c, "logo", updates); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed"})
+		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -115,3 +168,192 @@ func (h *SettingsHandler) UpdateLogo(c *gin.Context) {
 		"collapsedLogoUrl": collapsedValue,
 	})
 }
+
+func (h *SettingsHandler) GetCompany(c *gin.Context) {
+	values, err := loadSettings(h.DB, companyNameSettingKey, companyAddrSettingKey, companyEmailSettingKey, companyPhoneSettingKey, companyTaxIDSettingKey)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load company"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"name":    values[companyNameSettingKey],
+		"address": values[companyAddrSettingKey],
+		"email":   values[companyEmailSettingKey],
+		"phone":   values[companyPhoneSettingKey],
+		"taxId":   values[companyTaxIDSettingKey],
+	})
+}
+
+func (h *SettingsHandler) UpdateCompany(c *gin.Context) {
+	var req updateCompanyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name cannot be empty"})
+		return
+	}
+
+	updates := map[string]string{
+		companyNameSettingKey:  name,
+		companyAddrSettingKey:  strings.TrimSpace(req.Address),
+		companyEmailSettingKey: strings.TrimSpace(req.Email),
+		companyPhoneSettingKey: strings.TrimSpace(req.Phone),
+		companyTaxIDSettingKey: strings.TrimSpace(req.TaxID),
+	}
+
+	if err := saveSettings(h.DB, c, "company", updates); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"name":    updates[companyNameSettingKey],
+		"address": updates[companyAddrSettingKey],
+		"email":   updates[companyEmailSettingKey],
+		"phone":   updates[companyPhoneSettingKey],
+		"taxId":   updates[companyTaxIDSettingKey],
+	})
+}
+
+func (h *SettingsHandler) GetInvoiceNumbering(c *gin.Context) {
+	var sequence models.NumberSequence
+	if err := h.DB.First(&sequence, "`key` = ?", invoiceSequenceKey).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load numbering"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sequence": sequence,
+		"preview":  formatSequenceNumber(sequence, sequence.NextValue),
+	})
+}
+
+func (h *SettingsHandler) UpdateInvoiceNumbering(c *gin.Context) {
+	var req updateNumberSequenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	prefix := strings.ToUpper(strings.TrimSpace(req.Prefix))
+	if len(prefix) > 20 || strings.Contains(prefix, " ") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid prefix"})
+		return
+	}
+	if req.Padding < 1 || req.Padding > 10 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "padding must be between 1 and 10"})
+		return
+	}
+	if req.NextValue != nil && *req.NextValue < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "nextValue must be positive"})
+		return
+	}
+
+	var sequence models.NumberSequence
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&sequence, "`key` = ?", invoiceSequenceKey).Error; err != nil {
+			return err
+		}
+		before := sequence
+		if req.YearlyReset && !sequence.YearlyReset {
+			sequence.Year = time.Now().Year()
+		}
+		sequence.Prefix = prefix
+		sequence.YearlyReset = req.YearlyReset
+		sequence.Padding = req.Padding
+		if req.NextValue != nil {
+			sequence.NextValue = *req.NextValue
+		}
+		if err := tx.Save(&sequence).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionUpdate, "number_sequence", sequence.Key, before, sequence)
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"sequence": sequence,
+		"preview":  formatSequenceNumber(sequence, sequence.NextValue),
+	})
+}
+
+func (h *SettingsHandler) GetCurrency(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"baseCurrency": baseCurrency(h.DB)})
+}
+
+func (h *SettingsHandler) UpdateCurrency(c *gin.Context) {
+	var req updateCurrencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	currency, ok := normalizeCurrency(req.BaseCurrency)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid currency"})
+		return
+	}
+
+	if err := saveSettings(h.DB, c, "currency", map[string]string{baseCurrencySettingKey: currency}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"baseCurrency": currency})
+}
+
+func (h *SettingsHandler) GetReminders(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"offsets": reminderOffsets(h.DB)})
+}
+
+func (h *SettingsHandler) UpdateReminders(c *gin.Context) {
+	var req updateRemindersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	offsets, ok := parseReminderOffsets(formatReminderOffsets(req.Offsets))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "offsets must be between 1 and 365 days"})
+		return
+	}
+
+	if err := saveSettings(h.DB, c, "reminders", map[string]string{reminderOffsetsSettingKey: formatReminderOffsets(offsets)}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"offsets": offsets})
+}
+
+func (h *SettingsHandler) GetOvertime(c *gin.Context) {
+	rules, err := loadOvertimeRules(h.DB)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load overtime rules"})
+		return
+	}
+	c.JSON(http.StatusOK, rules)
+}
+
+func (h *SettingsHandler) UpdateOvertime(c *gin.Context) {
+	var rules overtimeRules
+	if err := c.ShouldBindJSON(&rules); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	if message := rules.validate(); message != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return
+	}
+
+	if err := saveSettings(h.DB, c, "overtime", rules.settings()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
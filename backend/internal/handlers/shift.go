@@ -0,0 +1,645 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"erp-backend/internal/middleware"
+	"erp-backend/internal/models"
+)
+
+type ShiftHandler struct {
+	DB *gorm.DB
+}
+
+const (
+	rosterStatusScheduled = "scheduled"
+	rosterStatusWorking   = "working"
+	rosterStatusPresent   = "present"
+	rosterStatusNoShow    = "no_show"
+	rosterStatusOnLeave   = "on_leave"
+
+	// maxRosterDays bounds the date range of roster listings and bulk
+	// assignments.
+	maxRosterDays = 92
+)
+
+// shiftCheckInWindow is how long before the shift start a check-in still
+// counts towards that shift.
+const shiftCheckInWindow = 4 * time.Hour
+
+// shiftDayNames is indexed by time.Weekday.
+var shiftDayNames = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+type shiftRequest struct {
+	Name         string   `json:"name"`
+	StartTime    string   `json:"startTime"`
+	EndTime      string   `json:"endTime"`
+	BreakMinutes int      `json:"breakMinutes"`
+	GraceMinutes int      `json:"graceMinutes"`
+	Days         []string `json:"days"`
+	Active       *bool    `json:"active"`
+}
+
+type assignRosterRequest struct {
+	EmployeeID string `json:"employeeId" binding:"required"`
+	ShiftID    string `json:"shiftId" binding:"required"`
+	From       string `json:"from" binding:"required"`
+	To         string `json:"to"`
+}
+
+// rosterEntry is a roster assignment compared with the attendance recorded
+// for it.
+type rosterEntry struct {
+	models.ShiftAssignment
+	ShiftStart   time.Time  `json:"shiftStart"`
+	ShiftEnd     time.Time  `json:"shiftEnd"`
+	AttendanceID *uuid.UUID `json:"attendanceId,omitempty"`
+	CheckIn      *time.Time `json:"checkIn,omitempty"`
+	CheckOut     *time.Time `json:"checkOut,omitempty"`
+	Status       string     `json:"status"`
+	Late         bool       `json:"late"`
+	LateMinutes  int        `json:"lateMinutes"`
+	LeftEarly    bool       `json:"leftEarly"`
+	EarlyMinutes int        `json:"earlyMinutes"`
+	// PlannedMinutes is the shift length less its break allowance;
+	// OverBreakMinutes is break time taken beyond the allowance.
+	PlannedMinutes   int `json:"plannedMinutes"`
+	WorkedMinutes    int `json:"workedMinutes"`
+	OverBreakMinutes int `json:"overBreakMinutes"`
+}
+
+func NewShiftHandler(db *gorm.DB) *ShiftHandler {
+	return &ShiftHandler{DB: db}
+}
+
+func parseClock(value string) (time.Time, bool) {
+	parsed, err := time.Parse("15:04", strings.TrimSpace(value))
+	return parsed, err == nil
+}
+
+// shiftWindow returns when shift starts and ends on date, in local time like
+// check-ins.
+func shiftWindow(shift models.Shift, date time.Time) (time.Time, time.Time) {
+	startClock, _ := parseClock(shift.StartTime)
+	endClock, _ := parseClock(shift.EndTime)
+	start := time.Date(date.Year(), date.Month(), date.Day(), startClock.Hour(), startClock.Minute(), 0, 0, time.Local)
+	end := time.Date(date.Year(), date.Month(), date.Day(), endClock.Hour(), endClock.Minute(), 0, 0, time.Local)
+	if !end.After(start) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return start, end
+}
+
+func shiftWorksOn(shift models.Shift, day time.Weekday) bool {
+	for _, name := range strings.Split(shift.Days, ",") {
+		if name == shiftDayNames[day] {
+			return true
+		}
+	}
+	return false
+}
+
+// applyShiftRequest validates req into shift.
+func applyShiftRequest(shift *models.Shift, req shiftRequest) string {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return "name required"
+	}
+	startClock, ok := parseClock(req.StartTime)
+	if !ok {
+		return "startTime must be HH:MM"
+	}
+	endClock, ok := parseClock(req.EndTime)
+	if !ok {
+		return "endTime must be HH:MM"
+	}
+	if req.BreakMinutes < 0 || req.GraceMinutes < 0 {
+		return "breakMinutes and graceMinutes cannot be negative"
+	}
+
+	selected := map[string]bool{}
+	for _, value := range req.Days {
+		day := strings.ToLower(strings.TrimSpace(value))
+		if len(day) > 3 {
+			day = day[:3]
+		}
+		valid := false
+		for _, name := range shiftDayNames {
+			valid = valid || name == day
+		}
+		if !valid {
+			return "invalid day " + value
+		}
+		selected[day] = true
+	}
+	if len(selected) == 0 {
+		return "at least one day required"
+	}
+	days := []string{}
+	for _, day := range []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday} {
+		if selected[shiftDayNames[day]] {
+			days = append(days, shiftDayNames[day])
+		}
+	}
+
+	shift.Name = name
+	shift.StartTime = startClock.Format("15:04")
+	shift.EndTime = endClock.Format("15:04")
+	shift.BreakMinutes = req.BreakMinutes
+	shift.GraceMinutes = req.GraceMinutes
+	shift.Days = strings.Join(days, ",")
+	if req.Active != nil {
+		shift.Active = *req.Active
+	}
+
+	start, end := shiftWindow(*shift, time.Now())
+	length := end.Sub(start)
+	if length > time.Duration(maxShiftHours)*time.Hour {
+		return "shift cannot be longer than the maximum shift length"
+	}
+	if time.Duration(shift.BreakMinutes)*time.Minute >= length {
+		return "break allowance must be shorter than the shift"
+	}
+	return ""
+}
+
+func (h *ShiftHandler) ListShifts(c *gin.Context) {
+	query := h.DB.Model(&models.Shift{})
+	if c.Query("active") == "true" {
+		query = query.Where("active = ?", true)
+	}
+	shifts := []models.Shift{}
+	if err := query.Order("start_time asc, name asc").Find(&shifts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load shifts"})
+		return
+	}
+	c.JSON(http.StatusOK, shifts)
+}
+
+func (h *ShiftHandler) CreateShift(c *gin.Context) {
+	var req shiftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+
+	shift := models.Shift{Active: true}
+	if message := applyShiftRequest(&shift, req); message != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return
+	}
+	var count int64
+	if err := h.DB.Model(&models.Shift{}).Where("name = ?", shift.Name).Count(&count).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "create failed"})
+		return
+	}
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "shift name already exists"})
+		return
+	}
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&shift).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionCreate, "shift", shift.ID, nil, shift)
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "create failed"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, shift)
+}
+
+// UpdateShift changes a template. Existing roster assignments follow the
+// new times.
+func (h *ShiftHandler) UpdateShift(c *gin.Context) {
+	var req shiftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	shiftID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var shift models.Shift
+	if err := h.DB.First(&shift, "id = ?", shiftID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "shift not found"})
+		return
+	}
+	before := shift
+	if message := applyShiftRequest(&shift, req); message != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return
+	}
+	var count int64
+	if err := h.DB.Model(&models.Shift{}).Where("name = ? AND id <> ?", shift.Name, shift.ID).Count(&count).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed"})
+		return
+	}
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "shift name already exists"})
+		return
+	}
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&shift).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionUpdate, "shift", shift.ID, before, shift)
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, shift)
+}
+
+// DeleteShift removes a template that was never rostered. Shifts with
+// assignments are kept for the history and can be deactivated instead.
+func (h *ShiftHandler) DeleteShift(c *gin.Context) {
+	shiftID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var shift models.Shift
+	if err := h.DB.First(&shift, "id = ?", shiftID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "shift not found"})
+		return
+	}
+	var count int64
+	if err := h.DB.Model(&models.ShiftAssignment{}).Where("shift_id = ?", shift.ID).Count(&count).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "delete failed"})
+		return
+	}
+	if count > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "shift is on the roster, deactivate it instead"})
+		return
+	}
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.Shift{}, "id = ?", shift.ID).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionDelete, "shift", shift.ID, shift, nil)
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "delete failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
+
+// compareRoster matches each assignment with the first attendance record
+// checked in from shiftCheckInWindow before the shift start until its end,
+// and flags late arrivals, early departures and no-shows. The grace period
+// of the shift applies to both ends, and breaks beyond the shift's break
+// allowance are reported as over-break time. Days covered by approved
+// leave are never no-shows.
+func compareRoster(db *gorm.DB, assignments []models.ShiftAssignment, now time.Time) ([]rosterEntry, error) {
+	entries := make([]rosterEntry, 0, len(assignments))
+	if len(assignments) == 0 {
+		return entries, nil
+	}
+
+	employeeIDs := []uuid.UUID{}
+	var first, last time.Time
+	for index, assignment := range assignments {
+		if !containsUUID(employeeIDs, assignment.EmployeeID) {
+			employeeIDs = append(employeeIDs, assignment.EmployeeID)
+		}
+		entry := rosterEntry{ShiftAssignment: assignment}
+		if assignment.Shift != nil {
+			entry.ShiftStart, entry.ShiftEnd = shiftWindow(*assignment.Shift, assignment.Date)
+			entry.PlannedMinutes = int(entry.ShiftEnd.Sub(entry.ShiftStart)/time.Minute) - assignment.Shift.BreakMinutes
+		}
+		if index == 0 || entry.ShiftStart.Before(first) {
+			first = entry.ShiftStart
+		}
+		if entry.ShiftEnd.After(last) {
+			last = entry.ShiftEnd
+		}
+		entries = append(entries, entry)
+	}
+
+	var records []models.Attendance
+	if err := db.Preload("Breaks").
+		Where("employee_id IN ? AND check_in >= ? AND check_in < ?", employeeIDs, first.Add(-shiftCheckInWindow), last).
+		Order("check_in asc").Find(&records).Error; err != nil {
+		return nil, err
+	}
+	var leaves []models.LeaveRequest
+	if err := db.Where("employee_id IN ? AND status = ? AND start_date <= ? AND end_date >= ?",
+		employeeIDs, "approved", dateOnly(last), dateOnly(first)).
+		Find(&leaves).Error; err != nil {
+		return nil, err
+	}
+
+	used := map[uuid.UUID]bool{}
+	for index := range entries {
+		entry := &entries[index]
+		if entry.Shift == nil {
+			entry.Status = rosterStatusScheduled
+			continue
+		}
+		grace := time.Duration(entry.Shift.GraceMinutes) * time.Minute
+		allowance := time.Duration(entry.Shift.BreakMinutes) * time.Minute
+
+		for _, record := range records {
+			if used[record.ID] || record.EmployeeID != entry.EmployeeID {
+				continue
+			}
+			if record.CheckIn.Before(entry.ShiftStart.Add(-shiftCheckInWindow)) || !record.CheckIn.Before(entry.ShiftEnd) {
+				continue
+			}
+			used[record.ID] = true
+			id, checkIn := record.ID, record.CheckIn
+			entry.AttendanceID = &id
+			entry.CheckIn = &checkIn
+			entry.CheckOut = record.CheckOut
+
+			end := now
+			if record.CheckOut != nil {
+				end = *record.CheckOut
+			}
+			var worked time.Duration
+			for _, span := range workedSpans(record, end) {
+				worked += span.end.Sub(span.start)
+			}
+			entry.WorkedMinutes = int(worked / time.Minute)
+			if taken := end.Sub(record.CheckIn) - worked; taken > allowance {
+				entry.OverBreakMinutes = int((taken - allowance) / time.Minute)
+			}
+			break
+		}
+
+		if entry.CheckIn == nil {
+			onLeave := false
+			for _, leave := range leaves {
+				day := dateOnly(entry.Date)
+				if leave.EmployeeID == entry.EmployeeID && !day.Before(dateOnly(leave.StartDate)) && !day.After(dateOnly(leave.EndDate)) {
+					onLeave = true
+					break
+				}
+			}
+			switch {
+			case onLeave:
+				entry.Status = rosterStatusOnLeave
+			case now.After(entry.ShiftEnd):
+				entry.Status = rosterStatusNoShow
+			default:
+				entry.Status = rosterStatusScheduled
+			}
+			continue
+		}
+
+		if late := entry.CheckIn.Sub(entry.ShiftStart); late > grace {
+			entry.Late = true
+			entry.LateMinutes = int(late / time.Minute)
+		}
+		if entry.CheckOut == nil {
+			entry.Status = rosterStatusWorking
+			continue
+		}
+		entry.Status = rosterStatusPresent
+		if early := entry.ShiftEnd.Sub(*entry.CheckOut); early > grace {
+			entry.LeftEarly = true
+			entry.EarlyMinutes = int(early / time.Minute)
+		}
+	}
+	return entries, nil
+}
+
+// ListRoster returns the assignments between from and to, this week by
+// default, compared with attendance. flag narrows the list to late,
+// left_early, over_break or no_show entries.
+func (h *ShiftHandler) ListRoster(c *gin.Context) {
+	from, to, message := parseDateRange(c)
+	if message != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return
+	}
+	if from.IsZero() {
+		from = mondayOf(utcToday())
+	}
+	if to.IsZero() {
+		to = from.AddDate(0, 0, 7)
+	}
+	if to.Sub(from) > maxRosterDays*24*time.Hour {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "date range is too long"})
+		return
+	}
+	flag := c.Query("flag")
+	if flag != "" && flag != "late" && flag != "left_early" && flag != "over_break" && flag != rosterStatusNoShow {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "flag must be late, left_early, over_break or no_show"})
+		return
+	}
+
+	query := h.DB.Model(&models.ShiftAssignment{}).Where("date >= ? AND date < ?", from, to)
+	role, _ := c.Get(middleware.ContextRole)
+	if role == "employee" {
+		employeeID, ok := contextEmployeeID(c)
+		if !ok {
+			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
+		query = query.Where("employee_id = ?", employeeID)
+	}
+	reports, scoped, err := managerScope(h.DB, c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load roster"})
+		return
+	}
+	if scoped {
+		query = query.Where("employee_id IN ?", reports)
+	}
+	if value := c.Query("employeeId"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employeeId"})
+			return
+		}
+		query = query.Where("employee_id = ?", id)
+	}
+
+	var assignments []models.ShiftAssignment
+	if err := query.Preload("Shift").Order("date asc").Find(&assignments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load roster"})
+		return
+	}
+	entries, err := compareRoster(h.DB, assignments, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load roster"})
+		return
+	}
+	if flag != "" {
+		filtered := []rosterEntry{}
+		for _, entry := range entries {
+			if (flag == "late" && entry.Late) || (flag == "left_early" && entry.LeftEarly) ||
+				(flag == "over_break" && entry.OverBreakMinutes > 0) || entry.Status == flag {
+				filtered = append(filtered, entry)
+			}
+		}
+		entries = filtered
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
+
+// AssignRoster puts an employee on a shift for every date from from to to
+// that the shift is worked on, replacing their earlier assignments on those
+// dates.
+func (h *ShiftHandler) AssignRoster(c *gin.Context) {
+	var req assignRosterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "employeeId, shiftId and from required"})
+		return
+	}
+	employeeID, err := uuid.Parse(req.EmployeeID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employeeId"})
+		return
+	}
+	shiftID, err := uuid.Parse(req.ShiftID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid shiftId"})
+		return
+	}
+	from, err := time.Parse("2006-01-02", req.From)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from"})
+		return
+	}
+	to := from
+	if req.To != "" {
+		to, err = time.Parse("2006-01-02", req.To)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to"})
+			return
+		}
+	}
+	if to.Before(from) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must not be after to"})
+		return
+	}
+	if to.Sub(from) >= maxRosterDays*24*time.Hour {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "date range is too long"})
+		return
+	}
+
+	employee, status, message := accessibleEmployeeByID(h.DB, c, employeeID, true)
+	if message != "" {
+		c.JSON(status, gin.H{"error": message})
+		return
+	}
+	if employee.Status == employeeStatusTerminated {
+		c.JSON(http.StatusConflict, gin.H{"error": "employee is terminated"})
+		return
+	}
+	var shift models.Shift
+	if err := h.DB.First(&shift, "id = ?", shiftID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "shift not found"})
+		return
+	}
+	if !shift.Active {
+		c.JSON(http.StatusConflict, gin.H{"error": "shift is inactive"})
+		return
+	}
+
+	assignments := []models.ShiftAssignment{}
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+			if !shiftWorksOn(shift, day.Weekday()) {
+				continue
+			}
+			if employee.TerminationDate != nil && day.After(dateOnly(*employee.TerminationDate)) {
+				break
+			}
+
+			var assignment models.ShiftAssignment
+			err := tx.Where("employee_id = ? AND date = ?", employee.ID, day).First(&assignment).Error
+			if err == nil {
+				if assignment.ShiftID == shift.ID {
+					assignments = append(assignments, assignment)
+					continue
+				}
+				before := assignment
+				assignment.ShiftID = shift.ID
+				assignment.AssignedBy = actorUserID(c)
+				if err := tx.Save(&assignment).Error; err != nil {
+					return err
+				}
+				if err := recordAudit(tx, c, auditActionUpdate, "shift_assignment", assignment.ID, before, assignment); err != nil {
+					return err
+				}
+			} else if err == gorm.ErrRecordNotFound {
+				assignment = models.ShiftAssignment{
+					EmployeeID: employee.ID,
+					Date:       day,
+					ShiftID:    shift.ID,
+					AssignedBy: actorUserID(c),
+				}
+				if err := tx.Create(&assignment).Error; err != nil {
+					return err
+				}
+				if err := recordAudit(tx, c, auditActionCreate, "shift_assignment", assignment.ID, nil, assignment); err != nil {
+					return err
+				}
+			} else {
+				return err
+			}
+			assignments = append(assignments, assignment)
+		}
+		return nil
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "assign failed"})
+		return
+	}
+	if len(assignments) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "shift is not worked on any of the dates"})
+		return
+	}
+
+	for index := range assignments {
+		assignments[index].Shift = &shift
+	}
+	c.JSON(http.StatusCreated, assignments)
+}
+
+func (h *ShiftHandler) DeleteRosterEntry(c *gin.Context) {
+	assignmentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var assignment models.ShiftAssignment
+	if err := h.DB.First(&assignment, "id = ?", assignmentID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "roster entry not found"})
+		return
+	}
+	if _, status, message := accessibleEmployeeByID(h.DB, c, assignment.EmployeeID, true); message != "" {
+		c.JSON(status, gin.H{"error": message})
+		return
+	}
+
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.ShiftAssignment{}, "id = ?", assignment.ID).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionDelete, "shift_assignment", assignment.ID, assignment, nil)
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "delete failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "deleted"})
+}
@@ -0,0 +1,448 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"erp-backend/internal/middleware"
+	"erp-backend/internal/models"
+)
+
+type TimesheetHandler struct {
+	DB *gorm.DB
+}
+
+const (
+	timesheetStatusSubmitted = "submitted"
+	timesheetStatusApproved  = "approved"
+	timesheetStatusRejected  = "rejected"
+	timesheetStatusReopened  = "reopened"
+)
+
+var errTimesheetLocked = errors.New("timesheet is approved")
+
+type submitTimesheetRequest struct {
+	EmployeeID string `json:"employeeId"`
+	WeekStart  string `json:"weekStart" binding:"required"`
+	Comment    string `json:"comment"`
+}
+
+type reviewTimesheetRequest struct {
+	Comment string `json:"comment"`
+}
+
+func NewTimesheetHandler(db *gorm.DB) *TimesheetHandler {
+	return &TimesheetHandler{DB: db}
+}
+
+// mondayOf returns the Monday starting the week of day, as a date.
+func mondayOf(day time.Time) time.Time {
+	date := dateOnly(day)
+	return date.AddDate(0, 0, -((int(date.Weekday()) + 6) % 7))
+}
+
+// timesheetLocked reports whether attendance checked in at the given time
+// belongs to an approved timesheet of the employee.
+func timesheetLocked(db *gorm.DB, employeeID uuid.UUID, checkIn time.Time) (bool, error) {
+	var count int64
+	if err := db.Model(&models.Timesheet{}).
+		Where("employee_id = ? AND week_start = ? AND status = ?", employeeID, mondayOf(checkIn), timesheetStatusApproved).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// timesheetLockedResponse writes the response for attendance that cannot be
+// changed because its timesheet is approved and reports whether it did.
+func timesheetLockedResponse(c *gin.Context, db *gorm.DB, employeeID uuid.UUID, checkIn time.Time) bool {
+	locked, err := timesheetLocked(db, employeeID, checkIn)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not check timesheet"})
+		return true
+	}
+	if locked {
+		c.JSON(http.StatusConflict, gin.H{"error": errTimesheetLocked.Error()})
+		return true
+	}
+	return false
+}
+
+// weekAttendance loads the attendance an employee checked in during the week
+// starting on monday.
+func weekAttendance(db *gorm.DB, employeeID uuid.UUID, monday time.Time) ([]models.Attendance, error) {
+	start := time.Date(monday.Year(), monday.Month(), monday.Day(), 0, 0, 0, 0, time.Local)
+	records := []models.Attendance{}
+	err := db.Preload("Breaks", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at asc")
+	}).Where("employee_id = ? AND check_in >= ? AND check_in < ?", employeeID, start, start.AddDate(0, 0, 7)).
+		Order("check_in asc").Find(&records).Error
+	return records, err
+}
+
+// timesheetEmployee resolves the employee a timesheet request is about:
+// the caller for employees, otherwise the employeeId given.
+func timesheetEmployee(db *gorm.DB, c *gin.Context, value string) (models.Employee, int, string) {
+	role, _ := c.Get(middleware.ContextRole)
+	if role == "employee" {
+		employeeID, ok := contextEmployeeID(c)
+		if !ok || (value != "" && value != employeeID.String()) {
+			return models.Employee{}, http.StatusForbidden, "forbidden"
+		}
+		value = employeeID.String()
+	}
+	if value == "" {
+		return models.Employee{}, http.StatusBadRequest, "employeeId required"
+	}
+	employeeID, err := uuid.Parse(value)
+	if err != nil {
+		return models.Employee{}, http.StatusBadRequest, "invalid employeeId"
+	}
+	return accessibleEmployeeByID(db, c, employeeID, false)
+}
+
+func parseWeekStart(value string) (time.Time, bool) {
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return mondayOf(parsed), true
+}
+
+var timesheetSortColumns = map[string]string{
+	"weekStart":   "week_start",
+	"submittedAt": "submitted_at",
+	"createdAt":   "created_at",
+}
+
+func (h *TimesheetHandler) List(c *gin.Context) {
+	params, message := parseListParams(c, timesheetSortColumns, "weekStart")
+	if message != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": message})
+		return
+	}
+
+	query := h.DB.Model(&models.Timesheet{})
+	role, _ := c.Get(middleware.ContextRole)
+	if role == "employee" {
+		employeeID, ok := contextEmployeeID(c)
+		if !ok {
+			c.JSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+			return
+		}
+		query = query.Where("employee_id = ?", employeeID)
+	}
+	reports, scoped, err := managerScope(h.DB, c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load timesheets"})
+		return
+	}
+	if scoped {
+		query = query.Where("employee_id IN ?", reports)
+	}
+	if value := c.Query("employeeId"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid employeeId"})
+			return
+		}
+		query = query.Where("employee_id = ?", id)
+	}
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if value := c.Query("weekStart"); value != "" {
+		monday, ok := parseWeekStart(value)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid weekStart"})
+			return
+		}
+		query = query.Where("week_start = ?", monday)
+	}
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load timesheets"})
+		return
+	}
+	timesheets := []models.Timesheet{}
+	if err := params.apply(query, "timesheets").Find(&timesheets).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load timesheets"})
+		return
+	}
+	c.JSON(http.StatusOK, pageResponse(timesheets, total, params))
+}
+
+// Week assembles the timesheet of one week from attendance, this week by
+// default, together with its approval state if it was submitted.
+func (h *TimesheetHandler) Week(c *gin.Context) {
+	employee, status, message := timesheetEmployee(h.DB, c, c.Query("employeeId"))
+	if message != "" {
+		c.JSON(status, gin.H{"error": message})
+		return
+	}
+	monday := mondayOf(time.Now())
+	if value := c.Query("weekStart"); value != "" {
+		var ok bool
+		if monday, ok = parseWeekStart(value); !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid weekStart"})
+			return
+		}
+	}
+
+	records, err := weekAttendance(h.DB, employee.ID, monday)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load timesheet"})
+		return
+	}
+	rules, err := loadOvertimeRules(h.DB)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load timesheet"})
+		return
+	}
+	summary, err := summarizeAttendance(h.DB, employee.ID, monday, monday.AddDate(0, 0, 7), rules, time.Now(), true)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load timesheet"})
+		return
+	}
+	summary.EmployeeName = employee.FirstName + " " + employee.LastName
+
+	var timesheet *models.Timesheet
+	var existing models.Timesheet
+	if err := h.DB.Where("employee_id = ? AND week_start = ?", employee.ID, monday).First(&existing).Error; err == nil {
+		timesheet = &existing
+	} else if err != gorm.ErrRecordNotFound {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "could not load timesheet"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"weekStart":  monday.Format("2006-01-02"),
+		"timesheet":  timesheet,
+		"attendance": records,
+		"summary":    summary,
+	})
+}
+
+// Submit puts a week up for approval. Weeks still being worked on, with an
+// open attendance record, cannot be submitted; rejected and reopened
+// timesheets are submitted again.
+func (h *TimesheetHandler) Submit(c *gin.Context) {
+	var req submitTimesheetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "weekStart required"})
+		return
+	}
+	monday, ok := parseWeekStart(req.WeekStart)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid weekStart"})
+		return
+	}
+	if monday.After(utcToday()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "week has not started"})
+		return
+	}
+	employee, status, message := timesheetEmployee(h.DB, c, req.EmployeeID)
+	if message != "" {
+		c.JSON(status, gin.H{"error": message})
+		return
+	}
+
+	records, err := weekAttendance(h.DB, employee.ID, monday)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "submit failed"})
+		return
+	}
+	for _, record := range records {
+		if record.CheckOut == nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "check out before submitting the week"})
+			return
+		}
+	}
+	rules, err := loadOvertimeRules(h.DB)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "submit failed"})
+		return
+	}
+	summary, err := summarizeAttendance(h.DB, employee.ID, monday, monday.AddDate(0, 0, 7), rules, time.Now(), false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "submit failed"})
+		return
+	}
+
+	var timesheet models.Timesheet
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		var before *models.Timesheet
+		err := tx.Where("employee_id = ? AND week_start = ?", employee.ID, monday).First(&timesheet).Error
+		if err == nil {
+			if timesheet.Status == timesheetStatusSubmitted || timesheet.Status == timesheetStatusApproved {
+				return gorm.ErrDuplicatedKey
+			}
+			previous := timesheet
+			before = &previous
+		} else if err == gorm.ErrRecordNotFound {
+			timesheet = models.Timesheet{EmployeeID: employee.ID, WeekStart: monday}
+		} else {
+			return err
+		}
+
+		now := time.Now()
+		timesheet.Status = timesheetStatusSubmitted
+		timesheet.WorkedHours = summary.Totals.Worked
+		timesheet.OvertimeHours = summary.Totals.Overtime
+		timesheet.SubmitComment = strings.TrimSpace(req.Comment)
+		timesheet.SubmittedBy = actorUserID(c)
+		timesheet.SubmittedAt = &now
+		timesheet.ReviewComment = ""
+		timesheet.ReviewedBy = nil
+		timesheet.ReviewedAt = nil
+		if before == nil {
+			if err := tx.Create(&timesheet).Error; err != nil {
+				return err
+			}
+			return recordAudit(tx, c, auditActionCreate, "timesheet", timesheet.ID, nil, timesheet)
+		}
+		if err := tx.Save(&timesheet).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionUpdate, "timesheet", timesheet.ID, *before, timesheet)
+	}); err != nil {
+		if err == gorm.ErrDuplicatedKey {
+			c.JSON(http.StatusConflict, gin.H{"error": "timesheet already " + timesheet.Status})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "submit failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, timesheet)
+}
+
+// review moves a timesheet from one of the from statuses to status, checking
+// the caller may manage its employee.
+func (h *TimesheetHandler) review(c *gin.Context, status string, comment string, from ...string) {
+	timesheetID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid id"})
+		return
+	}
+
+	var timesheet models.Timesheet
+	if err := h.DB.First(&timesheet, "id = ?", timesheetID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "timesheet not found"})
+		return
+	}
+	if _, code, message := accessibleEmployeeByID(h.DB, c, timesheet.EmployeeID, true); message != "" {
+		c.JSON(code, gin.H{"error": message})
+		return
+	}
+	allowed := false
+	for _, value := range from {
+		allowed = allowed || value == timesheet.Status
+	}
+	if !allowed {
+		c.JSON(http.StatusConflict, gin.H{"error": "timesheet is " + timesheet.Status})
+		return
+	}
+	if status == timesheetStatusApproved {
+		current, err := h.unchangedSinceSubmit(timesheet)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed"})
+			return
+		}
+		if !current {
+			c.JSON(http.StatusConflict, gin.H{"error": "attendance changed since the timesheet was submitted"})
+			return
+		}
+	}
+
+	before := timesheet
+	now := time.Now()
+	timesheet.Status = status
+	if status == timesheetStatusReopened {
+		timesheet.ReopenComment = comment
+		timesheet.ReopenedBy = actorUserID(c)
+		timesheet.ReopenedAt = &now
+	} else {
+		timesheet.ReviewComment = comment
+		timesheet.ReviewedBy = actorUserID(c)
+		timesheet.ReviewedAt = &now
+	}
+	if err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&timesheet).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, c, auditActionUpdate, "timesheet", timesheet.ID, before, timesheet)
+	}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "update failed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, timesheet)
+}
+
+// unchangedSinceSubmit reports whether the week's attendance still matches
+// the hours recorded when the timesheet was submitted, with every record
+// checked out.
+func (h *TimesheetHandler) unchangedSinceSubmit(timesheet models.Timesheet) (bool, error) {
+	monday := dateOnly(timesheet.WeekStart)
+	records, err := weekAttendance(h.DB, timesheet.EmployeeID, monday)
+	if err != nil {
+		return false, err
+	}
+	for _, record := range records {
+		if record.CheckOut == nil {
+			return false, nil
+		}
+	}
+	rules, err := loadOvertimeRules(h.DB)
+	if err != nil {
+		return false, err
+	}
+	summary, err := summarizeAttendance(h.DB, timesheet.EmployeeID, monday, monday.AddDate(0, 0, 7), rules, time.Now(), false)
+	if err != nil {
+		return false, err
+	}
+	return summary.Totals.Worked == timesheet.WorkedHours && summary.Totals.Overtime == timesheet.OvertimeHours, nil
+}
+
+// Approve signs off a submitted week, locking its attendance. Weeks whose
+// attendance changed after submission must be rejected and resubmitted.
+func (h *TimesheetHandler) Approve(c *gin.Context) {
+	var req reviewTimesheetRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid input"})
+		return
+	}
+	h.review(c, timesheetStatusApproved, strings.TrimSpace(req.Comment), timesheetStatusSubmitted)
+}
+
+// Reject sends a timesheet back to the employee, who can correct the week
+// and submit it again. A comment explaining why is required.
+func (h *TimesheetHandler) Reject(c *gin.Context) {
+	var req reviewTimesheetRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.Comment) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "comment required"})
+		return
+	}
+	h.review(c, timesheetStatusRejected, strings.TrimSpace(req.Comment), timesheetStatusSubmitted)
+}
+
+// Reopen unlocks the attendance of an approved timesheet so it can be
+// corrected and submitted again.
+func (h *TimesheetHandler) Reopen(c *gin.Context) {
+	var req reviewTimesheetRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.Comment) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "comment required"})
+		return
+	}
+	h.review(c, timesheetStatusReopened, strings.TrimSpace(req.Comment), timesheetStatusApproved)
+}
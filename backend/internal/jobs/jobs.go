@@ -0,0 +1,50 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Job is a unit of background work run periodically inside the server
+// process. Run receives the tick time.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(now time.Time) error
+}
+
+// Start runs every job once immediately and then on its interval until ctx is
+// cancelled. Each job runs in its own goroutine, and a failing or panicking
+// run is logged without stopping later runs.
+func Start(ctx context.Context, jobs ...Job) {
+	for _, job := range jobs {
+		go loop(ctx, job)
+	}
+}
+
+func loop(ctx context.Context, job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	runOnce(job, time.Now())
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			runOnce(job, now)
+		}
+	}
+}
+
+func runOnce(job Job, now time.Time) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			log.Printf("job %s panicked: %v", job.Name, recovered)
+		}
+	}()
+	if err := job.Run(now); err != nil {
+		log.Printf("job %s failed: %v", job.Name, err)
+	}
+}
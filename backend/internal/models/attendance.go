@@ -14,6 +14,7 @@ type Attendance struct {
 	CheckOut   *time.Time        `json:"checkOut,omitempty"`
 	Breaks     []AttendanceBreak `gorm:"foreignKey:AttendanceID;constraint:OnDelete:CASCADE" json:"breaks,omitempty"`
 	CreatedAt  time.Time         `json:"createdAt"`
+	DeletedAt  gorm.DeletedAt    `gorm:"index" json:"deletedAt"`
 }
 
 func (a *Attendance) BeforeCreate(tx *gorm.DB) error {
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AttendanceCorrection proposes new times for an attendance record. When
+// ReplaceBreaks is set the proposed breaks replace those of the record,
+// otherwise the record keeps its breaks.
+type AttendanceCorrection struct {
+	ID            uuid.UUID                   `gorm:"type:char(36);primaryKey" json:"id"`
+	EmployeeID    uuid.UUID                   `gorm:"type:char(36);index;not null" json:"employeeId"`
+	AttendanceID  uuid.UUID                   `gorm:"type:char(36);index;not null" json:"attendanceId"`
+	CheckIn       time.Time                   `gorm:"not null" json:"checkIn"`
+	CheckOut      time.Time                   `gorm:"not null" json:"checkOut"`
+	ReplaceBreaks bool                        `gorm:"not null" json:"replaceBreaks"`
+	Breaks        []AttendanceCorrectionBreak `gorm:"foreignKey:CorrectionID;constraint:OnDelete:CASCADE" json:"breaks"`
+	Reason        string                      `gorm:"size:500;not null" json:"reason"`
+	Status        string                      `gorm:"size:20;index;not null" json:"status"`
+	RequestedBy   *uuid.UUID                  `gorm:"type:char(36)" json:"requestedBy,omitempty"`
+	ReviewComment string                      `gorm:"size:500" json:"reviewComment"`
+	ReviewedBy    *uuid.UUID                  `gorm:"type:char(36)" json:"reviewedBy,omitempty"`
+	ReviewedAt    *time.Time                  `json:"reviewedAt,omitempty"`
+	CreatedAt     time.Time                   `json:"createdAt"`
+	UpdatedAt     time.Time                   `json:"updatedAt"`
+}
+
+func (c *AttendanceCorrection) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
+
+type AttendanceCorrectionBreak struct {
+	ID           uuid.UUID `gorm:"type:char(36);primaryKey" json:"id"`
+	CorrectionID uuid.UUID `gorm:"type:char(36);index;not null" json:"correctionId"`
+	BreakStart   time.Time `gorm:"not null" json:"breakStart"`
+	BreakEnd     time.Time `gorm:"not null" json:"breakEnd"`
+}
+
+func (b *AttendanceCorrectionBreak) BeforeCreate(tx *gorm.DB) error {
+	if b.ID == uuid.Nil {
+		b.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AuditEvent struct {
+	ID          uuid.UUID       `gorm:"type:char(36);primaryKey" json:"id"`
+	ActorUserID *uuid.UUID      `gorm:"type:char(36);index" json:"actorUserId,omitempty"`
+	ActorRole   string          `gorm:"size:50" json:"actorRole"`
+	Action      string          `gorm:"size:20;index;not null" json:"action"`
+	EntityType  string          `gorm:"size:50;index:idx_audit_entity;not null" json:"entityType"`
+	EntityID    string          `gorm:"size:64;index:idx_audit_entity" json:"entityId"`
+	Changes     json.RawMessage `gorm:"type:json" json:"changes"`
+	IP          string          `gorm:"size:64" json:"ip"`
+	CreatedAt   time.Time       `gorm:"index" json:"createdAt"`
+}
+
+func (a *AuditEvent) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
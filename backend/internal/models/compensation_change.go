@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CompensationChange is a salary set for an employee from EffectiveDate.
+// Several changes may share a date; the latest created one wins.
+type CompensationChange struct {
+	ID            uuid.UUID  `gorm:"type:char(36);primaryKey" json:"id"`
+	EmployeeID    uuid.UUID  `gorm:"type:char(36);index:idx_compensation_employee_date;not null" json:"employeeId"`
+	EffectiveDate time.Time  `gorm:"type:date;index:idx_compensation_employee_date;not null" json:"effectiveDate"`
+	Amount        float64    `gorm:"type:decimal(12,2);not null" json:"amount"`
+	Currency      string     `gorm:"size:3;not null" json:"currency"`
+	Reason        string     `gorm:"size:500" json:"reason"`
+	ApprovedBy    *uuid.UUID `gorm:"type:char(36)" json:"approvedBy,omitempty"`
+	AppliedAt     *time.Time `gorm:"index" json:"appliedAt,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+}
+
+func (c *CompensationChange) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
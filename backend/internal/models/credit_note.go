@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type CreditNote struct {
+	ID        uuid.UUID  `gorm:"type:char(36);primaryKey" json:"id"`
+	Number    string     `gorm:"uniqueIndex;size:100;not null" json:"number"`
+	InvoiceID uuid.UUID  `gorm:"type:char(36);index;not null" json:"invoiceId"`
+	Amount    float64    `gorm:"type:decimal(12,2);not null" json:"amount"`
+	Reason    string     `gorm:"size:500;not null" json:"reason"`
+	IssuedAt  time.Time  `gorm:"index;not null" json:"issuedAt"`
+	CreatedBy *uuid.UUID `gorm:"type:char(36)" json:"createdBy,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+func (n *CreditNote) BeforeCreate(tx *gorm.DB) error {
+	if n.ID == uuid.Nil {
+		n.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Customer struct {
+	ID               uuid.UUID `gorm:"type:char(36);primaryKey" json:"id"`
+	Name             string    `gorm:"uniqueIndex;size:255;not null" json:"name"`
+	BillingEmail     string    `gorm:"size:255" json:"billingEmail"`
+	Address          string    `gorm:"size:1000" json:"address"`
+	TaxID            string    `gorm:"size:100" json:"taxId"`
+	PaymentTermsDays int       `gorm:"not null;default:30" json:"paymentTermsDays"`
+	Currency         string    `gorm:"size:3;not null;default:USD" json:"currency"`
+	CreatedAt        time.Time `json:"createdAt"`
+	UpdatedAt        time.Time `json:"updatedAt"`
+}
+
+func (c *Customer) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Department struct {
+	ID          uuid.UUID `gorm:"type:char(36);primaryKey" json:"id"`
+	Name        string    `gorm:"uniqueIndex;size:120;not null" json:"name"`
+	Description string    `gorm:"size:500" json:"description"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+func (d *Department) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return nil
+}
@@ -8,17 +8,24 @@ import (
 )
 
 type Employee struct {
-	ID        uuid.UUID `gorm:"type:char(36);primaryKey" json:"id"`
-	FirstName string    `gorm:"size:120;not null" json:"firstName"`
-	LastName  string    `gorm:"size:120;not null" json:"lastName"`
-	Email     string    `gorm:"uniqueIndex;size:255;not null" json:"email"`
-	Role      string    `gorm:"size:50;not null;default:employee" json:"role"`
-	Phone     string    `gorm:"size:50" json:"phone"`
-	Position  string    `gorm:"size:120" json:"position"`
-	Salary    float64   `gorm:"type:decimal(12,2)" json:"salary"`
-	HiredAt   time.Time `json:"hiredAt"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
+	ID                uuid.UUID      `gorm:"type:char(36);primaryKey" json:"id"`
+	FirstName         string         `gorm:"size:120;not null" json:"firstName"`
+	LastName          string         `gorm:"size:120;not null" json:"lastName"`
+	Email             string         `gorm:"uniqueIndex;size:255;not null" json:"email"`
+	Role              string         `gorm:"size:50;not null;default:employee" json:"role"`
+	Phone             string         `gorm:"size:50" json:"phone"`
+	Position          string         `gorm:"size:120" json:"position"`
+	DepartmentID      *uuid.UUID     `gorm:"type:char(36);index" json:"departmentId,omitempty"`
+	ManagerID         *uuid.UUID     `gorm:"type:char(36);index" json:"managerId,omitempty"`
+	Salary            float64        `gorm:"type:decimal(12,2)" json:"salary"`
+	SalaryCurrency    string         `gorm:"size:3" json:"salaryCurrency"`
+	HiredAt           time.Time      `json:"hiredAt"`
+	Status            string         `gorm:"size:20;index;not null;default:active" json:"status"`
+	TerminationDate   *time.Time     `gorm:"type:date" json:"terminationDate,omitempty"`
+	TerminationReason string         `gorm:"size:500" json:"terminationReason,omitempty"`
+	CreatedAt         time.Time      `json:"createdAt"`
+	UpdatedAt         time.Time      `json:"updatedAt"`
+	DeletedAt         gorm.DeletedAt `gorm:"index" json:"deletedAt"`
 }
 
 func (e *Employee) BeforeCreate(tx *gorm.DB) error {
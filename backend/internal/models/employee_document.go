@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type EmployeeDocument struct {
+	ID          uuid.UUID  `gorm:"type:char(36);primaryKey" json:"id"`
+	EmployeeID  uuid.UUID  `gorm:"type:char(36);index;not null" json:"employeeId"`
+	Type        string     `gorm:"size:30;index;not null" json:"type"`
+	FileName    string     `gorm:"size:255;not null" json:"fileName"`
+	ContentType string     `gorm:"size:100;not null" json:"contentType"`
+	Size        int64      `gorm:"not null" json:"size"`
+	StorageKey  string     `gorm:"size:255;not null" json:"-"`
+	ExpiresAt   *time.Time `gorm:"type:date;index" json:"expiresAt,omitempty"`
+	UploadedBy  *uuid.UUID `gorm:"type:char(36)" json:"uploadedBy,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
+
+func (d *EmployeeDocument) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return nil
+}
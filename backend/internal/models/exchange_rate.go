@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ExchangeRate struct {
+	ID           uuid.UUID `gorm:"type:char(36);primaryKey" json:"id"`
+	Date         time.Time `gorm:"type:date;uniqueIndex:idx_exchange_rate_pair_date;not null" json:"date"`
+	FromCurrency string    `gorm:"size:3;uniqueIndex:idx_exchange_rate_pair_date;not null" json:"fromCurrency"`
+	ToCurrency   string    `gorm:"size:3;uniqueIndex:idx_exchange_rate_pair_date;not null" json:"toCurrency"`
+	Rate         float64   `gorm:"type:decimal(18,8);not null" json:"rate"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+func (r *ExchangeRate) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
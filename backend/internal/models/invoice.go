@@ -8,15 +8,28 @@ import (
 )
 
 type Invoice struct {
-	ID           uuid.UUID `gorm:"type:char(36);primaryKey" json:"id"`
-	Number       string    `gorm:"uniqueIndex;size:100;not null" json:"number"`
-	CustomerName string    `gorm:"size:255;not null" json:"customerName"`
-	Amount       float64   `gorm:"type:decimal(12,2);not null" json:"amount"`
-	Status       string    `gorm:"size:50;not null" json:"status"`
-	IssuedAt     time.Time `json:"issuedAt"`
-	DueAt        time.Time `json:"dueAt"`
-	CreatedAt    time.Time `json:"createdAt"`
-	UpdatedAt    time.Time `json:"updatedAt"`
+	ID           uuid.UUID      `gorm:"type:char(36);primaryKey" json:"id"`
+	Number       string         `gorm:"uniqueIndex;size:100;not null" json:"number"`
+	CustomerID   *uuid.UUID     `gorm:"type:char(36);index" json:"customerId,omitempty"`
+	CustomerName string         `gorm:"size:255;not null" json:"customerName"`
+	Currency     string         `gorm:"size:3;not null;default:USD" json:"currency"`
+	Subtotal     float64        `gorm:"type:decimal(12,2);not null;default:0" json:"subtotal"`
+	TaxTotal     float64        `gorm:"type:decimal(12,2);not null;default:0" json:"taxTotal"`
+	Amount       float64        `gorm:"type:decimal(12,2);not null" json:"amount"`
+	AmountPaid   float64        `gorm:"type:decimal(12,2);not null;default:0" json:"amountPaid"`
+	Credited     float64        `gorm:"type:decimal(12,2);not null;default:0" json:"credited"`
+	Status       string         `gorm:"size:50;index;not null" json:"status"`
+	IssuedAt     time.Time      `json:"issuedAt"`
+	DueAt        time.Time      `json:"dueAt"`
+	VoidedAt     *time.Time     `json:"voidedAt,omitempty"`
+	VoidedBy     *uuid.UUID     `gorm:"type:char(36)" json:"voidedBy,omitempty"`
+	VoidReason   string         `gorm:"size:500" json:"voidReason,omitempty"`
+	Lines        []InvoiceLine  `gorm:"foreignKey:InvoiceID;constraint:OnDelete:CASCADE" json:"lines"`
+	Payments     []Payment      `gorm:"foreignKey:InvoiceID;constraint:OnDelete:CASCADE" json:"payments,omitempty"`
+	CreditNotes  []CreditNote   `gorm:"foreignKey:InvoiceID;constraint:OnDelete:RESTRICT" json:"creditNotes,omitempty"`
+	CreatedAt    time.Time      `json:"createdAt"`
+	UpdatedAt    time.Time      `json:"updatedAt"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"deletedAt"`
 }
 
 func (i *Invoice) BeforeCreate(tx *gorm.DB) error {
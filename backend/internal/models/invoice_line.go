@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type InvoiceLine struct {
+	ID          uuid.UUID `gorm:"type:char(36);primaryKey" json:"id"`
+	InvoiceID   uuid.UUID `gorm:"type:char(36);index;not null" json:"invoiceId"`
+	Position    int       `gorm:"not null" json:"position"`
+	Description string    `gorm:"size:500;not null" json:"description"`
+	Quantity    float64   `gorm:"type:decimal(12,3);not null" json:"quantity"`
+	UnitPrice   float64   `gorm:"type:decimal(12,2);not null" json:"unitPrice"`
+	Discount    float64   `gorm:"type:decimal(5,2);not null;default:0" json:"discount"`
+	TaxRate     float64   `gorm:"type:decimal(5,2);not null;default:0" json:"taxRate"`
+	Subtotal    float64   `gorm:"type:decimal(12,2);not null" json:"subtotal"`
+	TaxAmount   float64   `gorm:"type:decimal(12,2);not null" json:"taxAmount"`
+	Total       float64   `gorm:"type:decimal(12,2);not null" json:"total"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+func (l *InvoiceLine) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return nil
+}
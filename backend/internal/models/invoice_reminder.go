@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type InvoiceReminder struct {
+	ID         uuid.UUID `gorm:"type:char(36);primaryKey" json:"id"`
+	InvoiceID  uuid.UUID `gorm:"type:char(36);uniqueIndex:idx_invoice_reminder_offset;not null" json:"invoiceId"`
+	OffsetDays int       `gorm:"uniqueIndex:idx_invoice_reminder_offset;not null" json:"offsetDays"`
+	Recipient  string    `gorm:"size:255;not null" json:"recipient"`
+	SentAt     time.Time `json:"sentAt"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+func (r *InvoiceReminder) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+type NumberSequence struct {
+	Key         string    `gorm:"size:64;primaryKey" json:"key"`
+	Prefix      string    `gorm:"size:20;not null" json:"prefix"`
+	YearlyReset bool      `gorm:"not null;default:true" json:"yearlyReset"`
+	Padding     int       `gorm:"not null;default:5" json:"padding"`
+	Year        int       `gorm:"not null" json:"year"`
+	NextValue   int64     `gorm:"not null;default:1" json:"nextValue"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// NumberSequenceYear keeps the counter of a yearly reset sequence for years
+// other than the sequence's current one, so documents dated in an earlier
+// year continue that year's numbering.
+type NumberSequenceYear struct {
+	Key       string    `gorm:"size:64;primaryKey" json:"key"`
+	Year      int       `gorm:"primaryKey;autoIncrement:false" json:"year"`
+	NextValue int64     `gorm:"not null;default:1" json:"nextValue"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
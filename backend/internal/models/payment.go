@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Payment struct {
+	ID         uuid.UUID  `gorm:"type:char(36);primaryKey" json:"id"`
+	InvoiceID  uuid.UUID  `gorm:"type:char(36);index;not null" json:"invoiceId"`
+	Amount     float64    `gorm:"type:decimal(12,2);not null" json:"amount"`
+	PaidAt     time.Time  `gorm:"index;not null" json:"paidAt"`
+	Method     string     `gorm:"size:50;not null" json:"method"`
+	Reference  string     `gorm:"size:255" json:"reference"`
+	RecordedBy *uuid.UUID `gorm:"type:char(36)" json:"recordedBy,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+func (p *Payment) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
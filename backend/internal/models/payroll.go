@@ -0,0 +1,102 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PayrollRun holds the payslips of one monthly pay period. Finalized runs
+// lock their period.
+type PayrollRun struct {
+	ID          uuid.UUID  `gorm:"type:char(36);primaryKey" json:"id"`
+	Period      string     `gorm:"size:7;uniqueIndex;not null" json:"period"`
+	PeriodStart time.Time  `gorm:"type:date;not null" json:"periodStart"`
+	PeriodEnd   time.Time  `gorm:"type:date;not null" json:"periodEnd"`
+	Status      string     `gorm:"size:20;index;not null" json:"status"`
+	CreatedBy   *uuid.UUID `gorm:"type:char(36)" json:"createdBy,omitempty"`
+	FinalizedBy *uuid.UUID `gorm:"type:char(36)" json:"finalizedBy,omitempty"`
+	FinalizedAt *time.Time `json:"finalizedAt,omitempty"`
+	Payslips    []Payslip  `gorm:"foreignKey:PayrollRunID;constraint:OnDelete:CASCADE" json:"payslips,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+func (r *PayrollRun) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+type Payslip struct {
+	ID              uuid.UUID     `gorm:"type:char(36);primaryKey" json:"id"`
+	PayrollRunID    uuid.UUID     `gorm:"type:char(36);uniqueIndex:idx_payslip_run_employee;not null" json:"payrollRunId"`
+	EmployeeID      uuid.UUID     `gorm:"type:char(36);uniqueIndex:idx_payslip_run_employee;index;not null" json:"employeeId"`
+	EmployeeName    string        `gorm:"size:255;not null" json:"employeeName"`
+	Position        string        `gorm:"size:120" json:"position"`
+	Currency        string        `gorm:"size:3;not null" json:"currency"`
+	MonthlySalary   float64       `gorm:"type:decimal(12,2);not null" json:"monthlySalary"`
+	WorkingDays     float64       `gorm:"type:decimal(6,2);not null" json:"workingDays"`
+	PaidDays        float64       `gorm:"type:decimal(6,2);not null" json:"paidDays"`
+	UnpaidLeaveDays float64       `gorm:"type:decimal(6,2);not null" json:"unpaidLeaveDays"`
+	WorkedHours     float64       `gorm:"type:decimal(8,2);not null" json:"workedHours"`
+	OvertimeHours   float64       `gorm:"type:decimal(8,2);not null" json:"overtimeHours"`
+	BasePay         float64       `gorm:"type:decimal(12,2);not null" json:"basePay"`
+	OvertimePay     float64       `gorm:"type:decimal(12,2);not null" json:"overtimePay"`
+	Allowances      float64       `gorm:"type:decimal(12,2);not null" json:"allowances"`
+	Deductions      float64       `gorm:"type:decimal(12,2);not null" json:"deductions"`
+	GrossPay        float64       `gorm:"type:decimal(12,2);not null" json:"grossPay"`
+	NetPay          float64       `gorm:"type:decimal(12,2);not null" json:"netPay"`
+	EmailedAt       *time.Time    `json:"emailedAt,omitempty"`
+	EmailQueuedAt   *time.Time    `gorm:"index" json:"emailQueuedAt,omitempty"`
+	EmailError      string        `gorm:"size:255" json:"emailError,omitempty"`
+	Lines           []PayslipLine `gorm:"foreignKey:PayslipID;constraint:OnDelete:CASCADE" json:"lines,omitempty"`
+	CreatedAt       time.Time     `json:"createdAt"`
+}
+
+func (p *Payslip) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == uuid.Nil {
+		p.ID = uuid.New()
+	}
+	return nil
+}
+
+type PayslipLine struct {
+	ID        uuid.UUID `gorm:"type:char(36);primaryKey" json:"id"`
+	PayslipID uuid.UUID `gorm:"type:char(36);index;not null" json:"payslipId"`
+	Kind      string    `gorm:"size:20;not null" json:"kind"`
+	Name      string    `gorm:"size:120;not null" json:"name"`
+	Amount    float64   `gorm:"type:decimal(12,2);not null" json:"amount"`
+	Position  int       `gorm:"not null" json:"position"`
+}
+
+func (l *PayslipLine) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return nil
+}
+
+// PayrollComponent is a recurring allowance or deduction applied to every
+// payslip, or to one employee's when EmployeeID is set. Percent components
+// are a percentage of the base pay.
+type PayrollComponent struct {
+	ID          uuid.UUID  `gorm:"type:char(36);primaryKey" json:"id"`
+	Name        string     `gorm:"size:120;not null" json:"name"`
+	Kind        string     `gorm:"size:20;not null" json:"kind"`
+	Calculation string     `gorm:"size:20;not null" json:"calculation"`
+	Amount      float64    `gorm:"type:decimal(12,2);not null" json:"amount"`
+	EmployeeID  *uuid.UUID `gorm:"type:char(36);index" json:"employeeId,omitempty"`
+	Active      bool       `gorm:"not null" json:"active"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+func (c *PayrollComponent) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
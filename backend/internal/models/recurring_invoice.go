@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RecurringInvoice struct {
+	ID            uuid.UUID              `gorm:"type:char(36);primaryKey" json:"id"`
+	CustomerID    uuid.UUID              `gorm:"type:char(36);index;not null" json:"customerId"`
+	Currency      string                 `gorm:"size:3" json:"currency"`
+	Interval      string                 `gorm:"size:20;not null" json:"interval"`
+	StartAt       time.Time              `gorm:"type:date;not null" json:"startAt"`
+	NextRunAt     time.Time              `gorm:"type:date;index;not null" json:"nextRunAt"`
+	EndAt         *time.Time             `gorm:"type:date" json:"endAt,omitempty"`
+	RunCount      int                    `gorm:"not null;default:0" json:"runCount"`
+	Status        string                 `gorm:"size:20;index;not null" json:"status"`
+	AutoSend      bool                   `gorm:"not null;default:false" json:"autoSend"`
+	EmailCustomer bool                   `gorm:"not null;default:false" json:"emailCustomer"`
+	LastInvoiceID *uuid.UUID             `gorm:"type:char(36)" json:"lastInvoiceId,omitempty"`
+	LastRunAt     *time.Time             `json:"lastRunAt,omitempty"`
+	LastError     string                 `gorm:"size:500" json:"lastError,omitempty"`
+	Lines         []RecurringInvoiceLine `gorm:"foreignKey:RecurringInvoiceID;constraint:OnDelete:CASCADE" json:"lines"`
+	CreatedAt     time.Time              `json:"createdAt"`
+	UpdatedAt     time.Time              `json:"updatedAt"`
+}
+
+func (r *RecurringInvoice) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+type RecurringInvoiceLine struct {
+	ID                 uuid.UUID `gorm:"type:char(36);primaryKey" json:"id"`
+	RecurringInvoiceID uuid.UUID `gorm:"type:char(36);index;not null" json:"recurringInvoiceId"`
+	Position           int       `gorm:"not null" json:"position"`
+	Description        string    `gorm:"size:500;not null" json:"description"`
+	Quantity           float64   `gorm:"type:decimal(12,3);not null" json:"quantity"`
+	UnitPrice          float64   `gorm:"type:decimal(12,2);not null" json:"unitPrice"`
+	Discount           float64   `gorm:"type:decimal(5,2);not null;default:0" json:"discount"`
+	TaxRate            float64   `gorm:"type:decimal(5,2);not null;default:0" json:"taxRate"`
+}
+
+func (l *RecurringInvoiceLine) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == uuid.Nil {
+		l.ID = uuid.New()
+	}
+	return nil
+}
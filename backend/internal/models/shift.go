@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Shift is a working-time template. StartTime and EndTime are "15:04" clock
+// times; an end before the start runs past midnight. Days lists the
+// weekdays the shift is normally worked, e.g. "mon,tue,wed,thu,fri".
+type Shift struct {
+	ID           uuid.UUID `gorm:"type:char(36);primaryKey" json:"id"`
+	Name         string    `gorm:"size:100;uniqueIndex;not null" json:"name"`
+	StartTime    string    `gorm:"size:5;not null" json:"startTime"`
+	EndTime      string    `gorm:"size:5;not null" json:"endTime"`
+	BreakMinutes int       `gorm:"not null" json:"breakMinutes"`
+	GraceMinutes int       `gorm:"not null" json:"graceMinutes"`
+	Days         string    `gorm:"size:27;not null" json:"days"`
+	Active       bool      `gorm:"not null" json:"active"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+func (s *Shift) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+// ShiftAssignment puts an employee on a shift for one date of the roster.
+type ShiftAssignment struct {
+	ID         uuid.UUID  `gorm:"type:char(36);primaryKey" json:"id"`
+	EmployeeID uuid.UUID  `gorm:"type:char(36);uniqueIndex:idx_shift_assignment_employee_date;not null" json:"employeeId"`
+	Date       time.Time  `gorm:"type:date;uniqueIndex:idx_shift_assignment_employee_date;index;not null" json:"date"`
+	ShiftID    uuid.UUID  `gorm:"type:char(36);index;not null" json:"shiftId"`
+	Shift      *Shift     `gorm:"foreignKey:ShiftID" json:"shift,omitempty"`
+	AssignedBy *uuid.UUID `gorm:"type:char(36)" json:"assignedBy,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	UpdatedAt  time.Time  `json:"updatedAt"`
+}
+
+func (a *ShiftAssignment) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
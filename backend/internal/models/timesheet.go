@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Timesheet is an employee's week of attendance, from Monday, put up for
+// approval. Approved timesheets lock the attendance of their week.
+type Timesheet struct {
+	ID            uuid.UUID  `gorm:"type:char(36);primaryKey" json:"id"`
+	EmployeeID    uuid.UUID  `gorm:"type:char(36);uniqueIndex:idx_timesheet_employee_week;not null" json:"employeeId"`
+	WeekStart     time.Time  `gorm:"type:date;uniqueIndex:idx_timesheet_employee_week;index;not null" json:"weekStart"`
+	Status        string     `gorm:"size:20;index;not null" json:"status"`
+	WorkedHours   float64    `gorm:"type:decimal(8,2);not null" json:"workedHours"`
+	OvertimeHours float64    `gorm:"type:decimal(8,2);not null" json:"overtimeHours"`
+	SubmitComment string     `gorm:"size:500" json:"submitComment"`
+	SubmittedBy   *uuid.UUID `gorm:"type:char(36)" json:"submittedBy,omitempty"`
+	SubmittedAt   *time.Time `json:"submittedAt,omitempty"`
+	ReviewComment string     `gorm:"size:500" json:"reviewComment"`
+	ReviewedBy    *uuid.UUID `gorm:"type:char(36)" json:"reviewedBy,omitempty"`
+	ReviewedAt    *time.Time `json:"reviewedAt,omitempty"`
+	ReopenComment string     `gorm:"size:500" json:"reopenComment"`
+	ReopenedBy    *uuid.UUID `gorm:"type:char(36)" json:"reopenedBy,omitempty"`
+	ReopenedAt    *time.Time `json:"reopenedAt,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+}
+
+func (t *Timesheet) BeforeCreate(tx *gorm.DB) error {
+	if t.ID == uuid.Nil {
+		t.ID = uuid.New()
+	}
+	return nil
+}
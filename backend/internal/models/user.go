@@ -15,6 +15,7 @@ type User struct {
 	Role         string     `gorm:"size:50;not null" json:"role"`
 	AvatarURL    string     `gorm:"size:2048" json:"avatarUrl,omitempty"`
 	EmployeeID   *uuid.UUID `gorm:"type:char(36);index" json:"employeeId,omitempty"`
+	DisabledAt   *time.Time `json:"disabledAt,omitempty"`
 	CreatedAt    time.Time  `json:"createdAt"`
 	UpdatedAt    time.Time  `json:"updatedAt"`
 }
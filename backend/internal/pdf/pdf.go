@@ -0,0 +1,187 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"io"
+	"strings"
+)
+
+// Page dimensions for A4 in PDF points.
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+type pdfImage struct {
+	name   string
+	width  int
+	height int
+	data   []byte
+}
+
+// Document is a minimal PDF writer supporting the standard Helvetica fonts,
+// lines, filled rectangles and raster images. Coordinates are in points with
+// the origin at the top-left corner of the page.
+type Document struct {
+	pages   []*bytes.Buffer
+	current *bytes.Buffer
+	images  []pdfImage
+}
+
+func New() *Document {
+	d := &Document{}
+	d.AddPage()
+	return d
+}
+
+func (d *Document) AddPage() {
+	d.current = &bytes.Buffer{}
+	d.pages = append(d.pages, d.current)
+}
+
+// Text draws s with its baseline at (x, y).
+func (d *Document) Text(x, y, size float64, bold bool, s string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(d.current, "BT /%s %.2f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, PageHeight-y, escape(s))
+}
+
+// TextRight draws s so that it ends at x.
+func (d *Document) TextRight(x, y, size float64, bold bool, s string) {
+	d.Text(x-TextWidth(s, size, bold), y, size, bold, s)
+}
+
+func (d *Document) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(d.current, "%.2f w %.2f %.2f m %.2f %.2f l S\n", width, x1, PageHeight-y1, x2, PageHeight-y2)
+}
+
+// FillRect fills a rectangle whose top-left corner is (x, y) with a gray
+// level between 0 (black) and 1 (white).
+func (d *Document) FillRect(x, y, w, h, gray float64) {
+	fmt.Fprintf(d.current, "%.3f g %.2f %.2f %.2f %.2f re f 0 g\n", gray, x, PageHeight-y-h, w, h)
+}
+
+// Image draws img into the box whose top-left corner is (x, y). Transparent
+// pixels are composited onto white.
+func (d *Document) Image(img image.Image, x, y, w, h float64) error {
+	bounds := img.Bounds()
+	raw := make([]byte, 0, bounds.Dx()*bounds.Dy()*3)
+	for py := bounds.Min.Y; py < bounds.Max.Y; py++ {
+		for px := bounds.Min.X; px < bounds.Max.X; px++ {
+			r, g, b, a := img.At(px, py).RGBA()
+			white := 0xffff - a
+			raw = append(raw, byte((r+white)>>8), byte((g+white)>>8), byte((b+white)>>8))
+		}
+	}
+
+	var compressed bytes.Buffer
+	writer := zlib.NewWriter(&compressed)
+	if _, err := writer.Write(raw); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("Im%d", len(d.images)+1)
+	d.images = append(d.images, pdfImage{
+		name:   name,
+		width:  bounds.Dx(),
+		height: bounds.Dy(),
+		data:   compressed.Bytes(),
+	})
+	fmt.Fprintf(d.current, "q %.2f 0 0 %.2f %.2f %.2f cm /%s Do Q\n", w, h, x, PageHeight-y-h, name)
+	return nil
+}
+
+func (d *Document) Bytes() []byte {
+	var out bytes.Buffer
+	_, _ = d.WriteTo(&out)
+	return out.Bytes()
+}
+
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	offsets := []int{}
+	object := func(body string, stream []byte) int {
+		offsets = append(offsets, buf.Len())
+		id := len(offsets)
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\n", id, body)
+		if stream != nil {
+			buf.WriteString("stream\n")
+			buf.Write(stream)
+			buf.WriteString("\nendstream\n")
+		}
+		buf.WriteString("endobj\n")
+		return id
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// Object ids are fixed up front: catalog, pages, two fonts, images, then
+	// a page and content stream per page.
+	firstImage := 5
+	firstPage := firstImage + len(d.images)
+	pageIDs := make([]string, 0, len(d.pages))
+	for index := range d.pages {
+		pageIDs = append(pageIDs, fmt.Sprintf("%d 0 R", firstPage+index*2))
+	}
+
+	object("<< /Type /Catalog /Pages 2 0 R >>", nil)
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(pageIDs, " "), len(d.pages)), nil)
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>", nil)
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>", nil)
+
+	xObjects := []string{}
+	for index, img := range d.images {
+		object(fmt.Sprintf("<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /FlateDecode /Length %d >>",
+			img.width, img.height, len(img.data)), img.data)
+		xObjects = append(xObjects, fmt.Sprintf("/%s %d 0 R", img.name, firstImage+index))
+	}
+
+	resources := "/Font << /F1 3 0 R /F2 4 0 R >>"
+	if len(xObjects) > 0 {
+		resources += " /XObject << " + strings.Join(xObjects, " ") + " >>"
+	}
+	for index, content := range d.pages {
+		contentID := firstPage + index*2 + 1
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << %s >> /Contents %d 0 R >>",
+			PageWidth, PageHeight, resources, contentID), nil)
+		object(fmt.Sprintf("<< /Length %d >>", content.Len()), content.Bytes())
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	n, err := w.Write(buf.Bytes())
+	return int64(n), err
+}
+
+// escape converts s to WinAnsi bytes and escapes PDF string delimiters.
+// Characters outside Latin-1 are replaced with '?'.
+func escape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(byte(r))
+		case r == '\n' || r == '\r' || r == '\t':
+			b.WriteByte(' ')
+		case r < 32 || r > 255 || (r > 126 && r < 160):
+			b.WriteByte('?')
+		default:
+			b.WriteByte(byte(r))
+		}
+	}
+	return b.String()
+}
@@ -10,9 +10,10 @@ import (
 	"erp-backend/internal/config"
 	"erp-backend/internal/handlers"
 	"erp-backend/internal/middleware"
+	"erp-backend/internal/storage"
 )
 
-func Register(router *gin.Engine, db *gorm.DB, cfg config.Config) {
+func Register(router *gin.Engine, db *gorm.DB, cfg config.Config, store storage.Storage) {
 	router.Use(corsMiddleware(cfg.AllowedOriginsRaw))
 
 	router.GET("/", func(c *gin.Context) {
@@ -25,11 +26,25 @@ func Register(router *gin.Engine, db *gorm.DB, cfg config.Config) {
 
 	authHandler := handlers.NewAuthHandler(db, cfg)
 	employeeHandler := handlers.NewEmployeeHandler(db)
+	employeeDocumentHandler := handlers.NewEmployeeDocumentHandler(db, store)
+	departmentHandler := handlers.NewDepartmentHandler(db)
+	customerHandler := handlers.NewCustomerHandler(db)
 	invoiceHandler := handlers.NewInvoiceHandler(db)
+	paymentHandler := handlers.NewPaymentHandler(db)
+	creditNoteHandler := handlers.NewCreditNoteHandler(db)
+	recurringInvoiceHandler := handlers.NewRecurringInvoiceHandler(db, cfg)
+	invoiceReminderHandler := handlers.NewInvoiceReminderHandler(db, cfg)
 	attendanceHandler := handlers.NewAttendanceHandler(db)
+	shiftHandler := handlers.NewShiftHandler(db)
+	timesheetHandler := handlers.NewTimesheetHandler(db)
 	dashboardHandler := handlers.NewDashboardHandler(db)
+	reportHandler := handlers.NewReportHandler(db)
+	searchHandler := handlers.NewSearchHandler(db)
+	auditHandler := handlers.NewAuditHandler(db)
 	leaveHandler := handlers.NewLeaveHandler(db)
+	payrollHandler := handlers.NewPayrollHandler(db, cfg)
 	settingsHandler := handlers.NewSettingsHandler(db)
+	exchangeRateHandler := handlers.NewExchangeRateHandler(db)
 
 	api := router.Group("/api")
 	{
@@ -50,23 +65,86 @@ func Register(router *gin.Engine, db *gorm.DB, cfg config.Config) {
 		protected.GET("/me", authHandler.Me)
 		protected.PUT("/me", authHandler.UpdateProfile)
 		protected.PUT("/me/password", authHandler.ChangePassword)
+		protected.GET("/me/payslips", payrollHandler.MyPayslips)
+		protected.GET("/me/payslips/:id/pdf", payrollHandler.MyPayslipPDF)
 		protected.GET("/dashboard", dashboardHandler.Get)
+		protected.GET("/search", searchHandler.Search)
+		protected.GET("/audit", middleware.RequireRole("admin"), auditHandler.List)
+		protected.GET("/reports/receivables-aging", middleware.RequireAnyRole("admin", "manager"), reportHandler.ReceivablesAging)
 		protected.GET("/settings/logo", middleware.RequireAnyRole("admin", "manager", "employee"), settingsHandler.GetLogo)
 		protected.PUT("/settings/logo", middleware.RequireAnyRole("admin", "manager"), settingsHandler.UpdateLogo)
+		protected.GET("/settings/company", middleware.RequireAnyRole("admin", "manager", "employee"), settingsHandler.GetCompany)
+		protected.PUT("/settings/company", middleware.RequireAnyRole("admin", "manager"), settingsHandler.UpdateCompany)
+		protected.GET("/settings/invoice-numbering", middleware.RequireAnyRole("admin", "manager"), settingsHandler.GetInvoiceNumbering)
+		protected.PUT("/settings/invoice-numbering", middleware.RequireRole("admin"), settingsHandler.UpdateInvoiceNumbering)
+		protected.GET("/settings/reminders", middleware.RequireAnyRole("admin", "manager"), settingsHandler.GetReminders)
+		protected.PUT("/settings/reminders", middleware.RequireAnyRole("admin", "manager"), settingsHandler.UpdateReminders)
+		protected.GET("/settings/currency", middleware.RequireAnyRole("admin", "manager", "employee"), settingsHandler.GetCurrency)
+		protected.PUT("/settings/currency", middleware.RequireRole("admin"), settingsHandler.UpdateCurrency)
+		protected.GET("/settings/overtime", middleware.RequireAnyRole("admin", "manager"), settingsHandler.GetOvertime)
+		protected.PUT("/settings/overtime", middleware.RequireRole("admin"), settingsHandler.UpdateOvertime)
+
+		protected.GET("/exchange-rates", middleware.RequireAnyRole("admin", "manager"), exchangeRateHandler.List)
+		protected.POST("/exchange-rates", middleware.RequireRole("admin"), exchangeRateHandler.Create)
+		protected.DELETE("/exchange-rates/:id", middleware.RequireRole("admin"), exchangeRateHandler.Delete)
+
+		protected.GET("/departments", middleware.RequireAnyRole("admin", "manager"), departmentHandler.List)
+		protected.POST("/departments", middleware.RequireRole("admin"), departmentHandler.Create)
+		protected.PUT("/departments/:id", middleware.RequireRole("admin"), departmentHandler.Update)
+		protected.DELETE("/departments/:id", middleware.RequireRole("admin"), departmentHandler.Delete)
+		protected.GET("/org-chart", middleware.RequireAnyRole("admin", "manager", "employee"), employeeHandler.OrgChart)
 
 		protected.GET("/employees", employeeHandler.List)
 		protected.POST("/employees", middleware.RequireAnyRole("admin", "manager"), employeeHandler.Create)
+		protected.POST("/employees/import", middleware.RequireAnyRole("admin", "manager"), employeeHandler.Import)
+		protected.GET("/employees/export", middleware.RequireAnyRole("admin", "manager"), employeeHandler.Export)
 		protected.PUT("/employees/:id", middleware.RequireAnyRole("admin", "manager"), employeeHandler.Update)
 		protected.DELETE("/employees/:id", middleware.RequireAnyRole("admin", "manager"), employeeHandler.Delete)
+		protected.POST("/employees/:id/restore", middleware.RequireRole("admin"), employeeHandler.Restore)
+		protected.POST("/employees/:id/terminate", middleware.RequireAnyRole("admin", "manager"), employeeHandler.Terminate)
 		protected.POST("/employees/:id/user", middleware.RequireAnyRole("admin", "manager"), employeeHandler.CreateUser)
 		protected.PUT("/employees/:id/user/password", middleware.RequireAnyRole("admin", "manager"), employeeHandler.UpsertUserPassword)
+		protected.GET("/employees/:id/compensation", middleware.RequireAnyRole("admin", "manager", "employee"), employeeHandler.ListCompensation)
+		protected.POST("/employees/:id/compensation", middleware.RequireAnyRole("admin", "manager"), employeeHandler.CreateCompensation)
+		protected.DELETE("/employees/:id/compensation/:changeId", middleware.RequireAnyRole("admin", "manager"), employeeHandler.DeleteCompensation)
+		protected.GET("/employees/:id/documents", middleware.RequireAnyRole("admin", "manager", "employee"), employeeDocumentHandler.List)
+		protected.POST("/employees/:id/documents", middleware.RequireAnyRole("admin", "manager"), employeeDocumentHandler.Upload)
+		protected.GET("/employees/:id/documents/:documentId/download", middleware.RequireAnyRole("admin", "manager", "employee"), employeeDocumentHandler.Download)
+		protected.DELETE("/employees/:id/documents/:documentId", middleware.RequireAnyRole("admin", "manager"), employeeDocumentHandler.Delete)
+
+		protected.GET("/customers", middleware.RequireAnyRole("admin", "manager"), customerHandler.List)
+		protected.GET("/customers/:id", middleware.RequireAnyRole("admin", "manager"), customerHandler.Get)
+		protected.POST("/customers", middleware.RequireAnyRole("admin", "manager"), customerHandler.Create)
+		protected.PUT("/customers/:id", middleware.RequireAnyRole("admin", "manager"), customerHandler.Update)
+		protected.DELETE("/customers/:id", middleware.RequireAnyRole("admin", "manager"), customerHandler.Delete)
 
 		protected.GET("/invoices", invoiceHandler.List)
 		protected.POST("/invoices", middleware.RequireAnyRole("admin", "manager"), invoiceHandler.Create)
 		protected.PUT("/invoices/:id", middleware.RequireAnyRole("admin", "manager"), invoiceHandler.Update)
 		protected.DELETE("/invoices/:id", middleware.RequireAnyRole("admin", "manager"), invoiceHandler.Delete)
+		protected.POST("/invoices/:id/restore", middleware.RequireRole("admin"), invoiceHandler.Restore)
+		protected.GET("/invoices/:id/pdf", middleware.RequireAnyRole("admin", "manager"), invoiceHandler.PDF)
+		protected.POST("/invoices/:id/void", middleware.RequireAnyRole("admin", "manager"), invoiceHandler.Void)
+		protected.GET("/invoices/:id/credit-notes", middleware.RequireAnyRole("admin", "manager"), creditNoteHandler.List)
+		protected.POST("/invoices/:id/credit-notes", middleware.RequireAnyRole("admin", "manager"), creditNoteHandler.Create)
+		protected.GET("/invoices/:id/reminders", middleware.RequireAnyRole("admin", "manager"), invoiceReminderHandler.List)
+		protected.GET("/credit-notes", middleware.RequireAnyRole("admin", "manager"), creditNoteHandler.List)
+
+		protected.GET("/recurring-invoices", middleware.RequireAnyRole("admin", "manager"), recurringInvoiceHandler.List)
+		protected.POST("/recurring-invoices", middleware.RequireAnyRole("admin", "manager"), recurringInvoiceHandler.Create)
+		protected.PUT("/recurring-invoices/:id", middleware.RequireAnyRole("admin", "manager"), recurringInvoiceHandler.Update)
+		protected.DELETE("/recurring-invoices/:id", middleware.RequireAnyRole("admin", "manager"), recurringInvoiceHandler.Delete)
+		protected.GET("/invoices/:id/payments", middleware.RequireAnyRole("admin", "manager"), paymentHandler.List)
+		protected.POST("/invoices/:id/payments", middleware.RequireAnyRole("admin", "manager"), paymentHandler.Create)
+		protected.DELETE("/invoices/:id/payments/:paymentId", middleware.RequireAnyRole("admin", "manager"), paymentHandler.Delete)
 
 		protected.GET("/attendance", middleware.RequireAnyRole("admin", "manager", "employee"), attendanceHandler.List)
+		protected.GET("/attendance/summary", middleware.RequireAnyRole("admin", "manager", "employee"), attendanceHandler.Summary)
+		protected.GET("/attendance/corrections", middleware.RequireAnyRole("admin", "manager", "employee"), attendanceHandler.ListCorrections)
+		protected.POST("/attendance/corrections", middleware.RequireAnyRole("admin", "manager", "employee"), attendanceHandler.CreateCorrection)
+		protected.POST("/attendance/corrections/:id/approve", middleware.RequireAnyRole("admin", "manager"), attendanceHandler.ApproveCorrection)
+		protected.POST("/attendance/corrections/:id/reject", middleware.RequireAnyRole("admin", "manager"), attendanceHandler.RejectCorrection)
+		protected.POST("/attendance/corrections/:id/cancel", middleware.RequireAnyRole("admin", "manager", "employee"), attendanceHandler.CancelCorrection)
 		protected.POST("/attendance/checkin", middleware.RequireAnyRole("admin", "manager", "employee"), attendanceHandler.CheckIn)
 		protected.POST("/attendance/break/start", middleware.RequireAnyRole("admin", "manager", "employee"), attendanceHandler.BreakStart)
 		protected.POST("/attendance/break/end", middleware.RequireAnyRole("admin", "manager", "employee"), attendanceHandler.BreakEnd)
@@ -80,7 +158,21 @@ func Register(router *gin.Engine, db *gorm.DB, cfg config.Config) {
 		protected.POST("/attendance/breaks/end", middleware.RequireAnyRole("admin", "manager", "employee"), attendanceHandler.BreakEnd)
 		protected.POST("/attendance/checkout", middleware.RequireAnyRole("admin", "manager", "employee"), attendanceHandler.CheckOut)
 		protected.DELETE("/attendance/:id", middleware.RequireAnyRole("admin", "manager"), attendanceHandler.Delete)
+		protected.POST("/attendance/:id/restore", middleware.RequireRole("admin"), attendanceHandler.Restore)
 		protected.DELETE("/attendance/employee/:employeeId", middleware.RequireAnyRole("admin", "manager"), attendanceHandler.DeleteByEmployee)
+		protected.GET("/shifts", middleware.RequireAnyRole("admin", "manager"), shiftHandler.ListShifts)
+		protected.POST("/shifts", middleware.RequireRole("admin"), shiftHandler.CreateShift)
+		protected.PUT("/shifts/:id", middleware.RequireRole("admin"), shiftHandler.UpdateShift)
+		protected.DELETE("/shifts/:id", middleware.RequireRole("admin"), shiftHandler.DeleteShift)
+		protected.GET("/roster", middleware.RequireAnyRole("admin", "manager", "employee"), shiftHandler.ListRoster)
+		protected.POST("/roster", middleware.RequireAnyRole("admin", "manager"), shiftHandler.AssignRoster)
+		protected.DELETE("/roster/:id", middleware.RequireAnyRole("admin", "manager"), shiftHandler.DeleteRosterEntry)
+		protected.GET("/timesheets", middleware.RequireAnyRole("admin", "manager", "employee"), timesheetHandler.List)
+		protected.GET("/timesheets/week", middleware.RequireAnyRole("admin", "manager", "employee"), timesheetHandler.Week)
+		protected.POST("/timesheets/submit", middleware.RequireAnyRole("admin", "manager", "employee"), timesheetHandler.Submit)
+		protected.POST("/timesheets/:id/approve", middleware.RequireAnyRole("admin", "manager"), timesheetHandler.Approve)
+		protected.POST("/timesheets/:id/reject", middleware.RequireAnyRole("admin", "manager"), timesheetHandler.Reject)
+		protected.POST("/timesheets/:id/reopen", middleware.RequireRole("admin"), timesheetHandler.Reopen)
 
 		protected.GET("/leave/requests", middleware.RequireAnyRole("admin", "manager", "employee"), leaveHandler.ListRequests)
 		protected.POST("/leave/requests", middleware.RequireAnyRole("admin", "manager", "employee"), leaveHandler.CreateRequest)
@@ -98,6 +190,19 @@ func Register(router *gin.Engine, db *gorm.DB, cfg config.Config) {
 		protected.GET("/leave/balances", middleware.RequireAnyRole("admin", "manager", "employee"), leaveHandler.ListBalances)
 		protected.GET("/leave/policies", middleware.RequireAnyRole("admin", "manager"), leaveHandler.ListPolicies)
 		protected.PUT("/leave/policies", middleware.RequireAnyRole("admin", "manager"), leaveHandler.UpdatePolicies)
+
+		protected.GET("/payroll/runs", middleware.RequireAnyRole("admin", "manager"), payrollHandler.ListRuns)
+		protected.POST("/payroll/runs", middleware.RequireAnyRole("admin", "manager"), payrollHandler.CreateRun)
+		protected.GET("/payroll/runs/:id", middleware.RequireAnyRole("admin", "manager"), payrollHandler.GetRun)
+		protected.POST("/payroll/runs/:id/recalculate", middleware.RequireAnyRole("admin", "manager"), payrollHandler.Recalculate)
+		protected.POST("/payroll/runs/:id/finalize", middleware.RequireRole("admin"), payrollHandler.Finalize)
+		protected.DELETE("/payroll/runs/:id", middleware.RequireRole("admin"), payrollHandler.DeleteRun)
+		protected.GET("/payroll/runs/:id/payslips/:payslipId/pdf", middleware.RequireAnyRole("admin", "manager"), payrollHandler.PayslipPDF)
+		protected.POST("/payroll/runs/:id/email", middleware.RequireRole("admin"), payrollHandler.EmailPayslips)
+		protected.GET("/payroll/components", middleware.RequireAnyRole("admin", "manager"), payrollHandler.ListComponents)
+		protected.POST("/payroll/components", middleware.RequireRole("admin"), payrollHandler.CreateComponent)
+		protected.PUT("/payroll/components/:id", middleware.RequireRole("admin"), payrollHandler.UpdateComponent)
+		protected.DELETE("/payroll/components/:id", middleware.RequireRole("admin"), payrollHandler.DeleteComponent)
 	}
 }
 
@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestLocalRoundTrip(t *testing.T) {
+	store, err := NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	testRoundTrip(t, store, "employees/42/contract.pdf")
+
+	if err := store.Delete(context.Background(), "employees/42/contract.pdf"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("delete missing: got %v, want ErrNotFound", err)
+	}
+}
+
+func TestLocalRejectsKeysOutsideRoot(t *testing.T) {
+	store, err := NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"../escape.txt", "a/../../escape.txt", ""} {
+		if err := store.Put(context.Background(), key, []byte("x"), "text/plain"); err == nil {
+			t.Errorf("put %q: expected an error", key)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+package storage
+
+import (
+	"os"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// TestS3RoundTrip runs against a live S3-compatible service, such as the
+// MinIO from docker-compose, configured through the same S3_* variables as
+// the server. It is skipped when S3_ENDPOINT is not set.
+func TestS3RoundTrip(t *testing.T) {
+	endpoint := os.Getenv("S3_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("S3_ENDPOINT not set")
+	}
+	store := NewS3(endpoint, os.Getenv("S3_REGION"), os.Getenv("S3_BUCKET"),
+		os.Getenv("S3_ACCESS_KEY"), os.Getenv("S3_SECRET_KEY"))
+
+	key := "storage-test/" + strconv.FormatInt(time.Now().UnixNano(), 10) + "/contract file.pdf"
+	testRoundTrip(t, store, key)
+}
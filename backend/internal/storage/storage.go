@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrNotFound is returned by Get and Delete when no object exists for a key.
+var ErrNotFound = errors.New("object not found")
+
+// Storage keeps uploaded files outside the database. Keys are slash
+// separated paths chosen by the caller.
+type Storage interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+type Config struct {
+	Backend     string
+	LocalDir    string
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+}
+
+// New returns the backend selected by cfg.Backend: "local" (the default) or
+// "s3".
+func New(cfg Config) (Storage, error) {
+	switch cfg.Backend {
+	case "", "local":
+		return NewLocal(cfg.LocalDir)
+	case "s3":
+		return NewS3(cfg.S3Endpoint, cfg.S3Region, cfg.S3Bucket, cfg.S3AccessKey, cfg.S3SecretKey), nil
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", cfg.Backend)
+	}
+}
@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+// testRoundTrip stores data under key, reads it back and deletes it, then
+// checks the object is gone.
+func testRoundTrip(t *testing.T, store Storage, key string) {
+	t.Helper()
+	ctx := context.Background()
+	data := []byte("payslip\x00\xffbinary")
+
+	if err := store.Put(ctx, key, data, "application/octet-stream"); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	reader, err := store.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	got, err := io.ReadAll(reader)
+	reader.Close()
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Fatalf("got %q, want %q", got, data)
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := store.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("get after delete: got %v, want ErrNotFound", err)
+	}
+}
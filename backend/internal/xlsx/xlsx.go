@@ -0,0 +1,322 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidWorkbook is returned when the archive is not a readable
+// spreadsheet.
+var ErrInvalidWorkbook = errors.New("xlsx: invalid workbook")
+
+// ErrTooManyRows is returned by ReadRows when the worksheet has more rows
+// than the caller allows.
+var ErrTooManyRows = errors.New("xlsx: too many rows")
+
+type workbookXML struct {
+	Sheets []struct {
+		ID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type relationshipsXML struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type richTextXML struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t richTextXML) String() string {
+	if len(t.Runs) == 0 {
+		return t.Text
+	}
+	var b strings.Builder
+	for _, run := range t.Runs {
+		b.WriteString(run.Text)
+	}
+	return b.String()
+}
+
+type sharedStringsXML struct {
+	Items []richTextXML `xml:"si"`
+}
+
+type rowXML struct {
+	Cells []struct {
+		Ref    string      `xml:"r,attr"`
+		Type   string      `xml:"t,attr"`
+		Value  string      `xml:"v"`
+		Inline richTextXML `xml:"is"`
+	} `xml:"c"`
+}
+
+const (
+	// maxPartSize bounds the uncompressed size of each part read from the
+	// archive.
+	maxPartSize = 32 << 20
+	// maxColumns is the number of columns of a worksheet, A to XFD.
+	maxColumns = 16384
+)
+
+// ReadRows returns the cell values of the first worksheet. Rows are padded
+// so that every cell sits at its column index; numbers are returned in their
+// stored form and dates as Excel serial numbers. When maxRows is positive,
+// reading stops with ErrTooManyRows once the sheet has more rows than that.
+func ReadRows(r io.ReaderAt, size int64, maxRows int) ([][]string, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, ErrInvalidWorkbook
+	}
+	files := map[string]*zip.File{}
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+
+	sheetPath, err := firstSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+
+	var shared sharedStringsXML
+	if file, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := decodeFile(file, &shared); err != nil {
+			return nil, err
+		}
+	}
+
+	file, ok := files[sheetPath]
+	if !ok {
+		return nil, ErrInvalidWorkbook
+	}
+	reader, err := openPart(file)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	rows := [][]string{}
+	decoder := xml.NewDecoder(io.LimitReader(reader, maxPartSize))
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, ErrInvalidWorkbook
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "row" {
+			continue
+		}
+		if maxRows > 0 && len(rows) >= maxRows {
+			return nil, ErrTooManyRows
+		}
+		var row rowXML
+		if err := decoder.DecodeElement(&row, &start); err != nil {
+			return nil, ErrInvalidWorkbook
+		}
+		values := []string{}
+		for i, cell := range row.Cells {
+			column := i
+			if cell.Ref != "" {
+				index, ok := columnIndex(cell.Ref)
+				if !ok {
+					return nil, ErrInvalidWorkbook
+				}
+				column = index
+			}
+			if column >= maxColumns {
+				return nil, ErrInvalidWorkbook
+			}
+			for len(values) <= column {
+				values = append(values, "")
+			}
+			switch cell.Type {
+			case "s":
+				index, err := strconv.Atoi(strings.TrimSpace(cell.Value))
+				if err != nil || index < 0 || index >= len(shared.Items) {
+					return nil, ErrInvalidWorkbook
+				}
+				values[column] = shared.Items[index].String()
+			case "inlineStr":
+				values[column] = cell.Inline.String()
+			default:
+				values[column] = cell.Value
+			}
+		}
+		rows = append(rows, values)
+	}
+	return rows, nil
+}
+
+func firstSheetPath(files map[string]*zip.File) (string, error) {
+	workbookFile, ok := files["xl/workbook.xml"]
+	if !ok {
+		return "", ErrInvalidWorkbook
+	}
+	var workbook workbookXML
+	if err := decodeFile(workbookFile, &workbook); err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", ErrInvalidWorkbook
+	}
+
+	relsFile, ok := files["xl/_rels/workbook.xml.rels"]
+	if !ok {
+		return "xl/worksheets/sheet1.xml", nil
+	}
+	var rels relationshipsXML
+	if err := decodeFile(relsFile, &rels); err != nil {
+		return "", err
+	}
+	for _, rel := range rels.Relationships {
+		if rel.ID != workbook.Sheets[0].ID {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+	return "", ErrInvalidWorkbook
+}
+
+// openPart opens a part of the archive, refusing parts whose declared size
+// is over maxPartSize. Readers must still be limited as the declared size
+// may not match the data.
+func openPart(file *zip.File) (io.ReadCloser, error) {
+	if file.UncompressedSize64 > maxPartSize {
+		return nil, ErrInvalidWorkbook
+	}
+	reader, err := file.Open()
+	if err != nil {
+		return nil, ErrInvalidWorkbook
+	}
+	return reader, nil
+}
+
+func decodeFile(file *zip.File, v any) error {
+	reader, err := openPart(file)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	if err := xml.NewDecoder(io.LimitReader(reader, maxPartSize)).Decode(v); err != nil {
+		return ErrInvalidWorkbook
+	}
+	return nil
+}
+
+// columnIndex converts the column letters of a cell reference such as "AB12"
+// to a zero based index. References past column XFD are rejected.
+func columnIndex(ref string) (int, bool) {
+	index := 0
+	letters := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		if letters == 3 {
+			return 0, false
+		}
+		index = index*26 + int(r-'A'+1)
+		letters++
+	}
+	if letters == 0 || index > maxColumns {
+		return 0, false
+	}
+	return index - 1, true
+}
+
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+const contentTypesXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+</Types>`
+
+const rootRelsXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+
+const workbookRelsXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+</Relationships>`
+
+// Write stores rows as a single worksheet named sheetName. All cells are
+// written as inline strings.
+func Write(w io.Writer, sheetName string, rows [][]string) error {
+	var name bytes.Buffer
+	if err := xml.EscapeText(&name, []byte(sheetName)); err != nil {
+		return err
+	}
+	workbook := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="` + name.String() + `" sheetId="1" r:id="rId1"/></sheets>
+</workbook>`
+
+	var sheet bytes.Buffer
+	sheet.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	for i, row := range rows {
+		fmt.Fprintf(&sheet, `<row r="%d">`, i+1)
+		for j, value := range row {
+			fmt.Fprintf(&sheet, `<c r="%s%d" t="inlineStr"><is><t xml:space="preserve">`, columnName(j), i+1)
+			if err := xml.EscapeText(&sheet, []byte(value)); err != nil {
+				return err
+			}
+			sheet.WriteString(`</t></is></c>`)
+		}
+		sheet.WriteString(`</row>`)
+	}
+	sheet.WriteString(`</sheetData></worksheet>`)
+
+	archive := zip.NewWriter(w)
+	parts := []struct {
+		name string
+		data []byte
+	}{
+		{"[Content_Types].xml", []byte(contentTypesXML)},
+		{"_rels/.rels", []byte(rootRelsXML)},
+		{"xl/workbook.xml", []byte(workbook)},
+		{"xl/_rels/workbook.xml.rels", []byte(workbookRelsXML)},
+		{"xl/worksheets/sheet1.xml", sheet.Bytes()},
+	}
+	for _, part := range parts {
+		file, err := archive.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := file.Write(part.data); err != nil {
+			return err
+		}
+	}
+	return archive.Close()
+}
@@ -1,5 +1,6 @@
 import axios from "axios";
 import { getAccessToken, clearTokens } from "../lib/authStorage";
+import type { Page } from "./types";
 
 type HttpMethod = "get" | "post" | "put" | "patch" | "delete";
 
@@ -39,6 +40,22 @@ export async function requestWithFallback<T = unknown>(
   throw lastError ?? new Error("request failed");
 }
 
+export const pageLimit = 50;
+export const maxPageLimit = 200;
+
+// fetchAllPages walks every page of a list endpoint, for lookups such as
+// select options that need the complete set rather than one page.
+export async function fetchAllPages<T>(url: string, params: Record<string, unknown> = {}) {
+  const items: T[] = [];
+  for (let page = 1; ; page += 1) {
+    const response = await api.get<Page<T>>(url, { params: { ...params, page, limit: maxPageLimit } });
+    items.push(...response.data.items);
+    if (response.data.items.length === 0 || items.length >= response.data.total) {
+      return items;
+    }
+  }
+}
+
 api.interceptors.request.use((config) => {
   const token = getAccessToken();
   if (token) {
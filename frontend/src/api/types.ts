@@ -22,6 +22,13 @@ export type DashboardMetrics = {
   currency: string;
 };
 
+export type Page<T> = {
+  items: T[];
+  total: number;
+  page: number;
+  limit: number;
+};
+
 export type Employee = {
   id: string;
   firstName: string;
@@ -32,13 +39,34 @@ export type Employee = {
   position?: string;
   salary?: number;
   hiredAt: string;
+  status?: "active" | "on_notice" | "terminated";
+  terminationDate?: string;
+  terminationReason?: string;
+  departmentId?: string | null;
+  managerId?: string | null;
+};
+
+export type Customer = {
+  id: string;
+  name: string;
+  currency: string;
+};
+
+export type InvoiceLine = {
+  description: string;
+  quantity: number;
+  unitPrice: number;
+  discount: number;
+  taxRate: number;
 };
 
 export type Invoice = {
   id: string;
   number: string;
+  customerId?: string;
   customerName: string;
   amount: number;
+  lines?: InvoiceLine[];
   status: string;
   issuedAt: string;
   dueAt: string;
@@ -62,12 +90,12 @@ export type AttendanceBreak = {
 export type LeaveRequest = {
   id: string;
   employeeId: string;
-  type: "sick" | "casual";
+  type: "sick" | "casual" | "unpaid";
   startDate: string;
   endDate: string;
   days: number;
   reason?: string;
-  status: "pending" | "approved" | "rejected";
+  status: "pending" | "approved" | "rejected" | "cancelled";
   approverId?: string;
   approvedAt?: string;
   createdAt: string;
@@ -77,7 +105,7 @@ export type LeaveBalance = {
   id: string;
   employeeId: string;
   year: number;
-  type: "sick" | "casual";
+  type: "sick" | "casual" | "unpaid";
   total: number;
   used: number;
 };
@@ -85,6 +113,6 @@ export type LeaveBalance = {
 export type LeavePolicy = {
   id: string;
   year: number;
-  type: "sick" | "casual";
+  type: "sick" | "casual" | "unpaid";
   total: number;
 };
@@ -0,0 +1,30 @@
+export default function Pager({
+  page,
+  limit,
+  total,
+  onChange
+}: {
+  page: number;
+  limit: number;
+  total: number;
+  onChange: (page: number) => void;
+}) {
+  const pages = Math.max(1, Math.ceil(total / limit));
+  if (pages <= 1) {
+    return null;
+  }
+
+  return (
+    <div className="pager">
+      <button className="ghost" type="button" disabled={page <= 1} onClick={() => onChange(page - 1)}>
+        Previous
+      </button>
+      <span className="helper">
+        Page {page} of {pages} ({total} total)
+      </span>
+      <button className="ghost" type="button" disabled={page >= pages} onClick={() => onChange(page + 1)}>
+        Next
+      </button>
+    </div>
+  );
+}
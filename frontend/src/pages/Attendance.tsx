@@ -1,8 +1,9 @@
 import { useEffect, useMemo, useState } from "react";
 import axios from "axios";
-import api, { requestWithFallback } from "../api/client";
-import type { Attendance, AttendanceBreak, Employee, User } from "../api/types";
+import api, { fetchAllPages, pageLimit, requestWithFallback } from "../api/client";
+import type { Attendance, AttendanceBreak, Employee, Page, User } from "../api/types";
 import { me } from "../api/auth";
+import Pager from "../components/Pager";
 
 function getCurrentTimeParts() {
   const now = new Date();
@@ -56,6 +57,10 @@ function formatDateLabel(dateKey: string) {
 export default function Attendance() {
   const todayDate = formatDateKey(new Date());
   const [attendance, setAttendance] = useState<Attendance[]>([]);
+  const [attendancePage, setAttendancePage] = useState(1);
+  const [attendanceTotal, setAttendanceTotal] = useState(0);
+  const [openAttendance, setOpenAttendance] = useState<Attendance[]>([]);
+  const [todayAttendance, setTodayAttendance] = useState<Attendance[]>([]);
   const [employees, setEmployees] = useState<Employee[]>([]);
   const [manualEmployeeId, setManualEmployeeId] = useState("");
   const [manualAttendanceId, setManualAttendanceId] = useState("");
@@ -80,21 +85,52 @@ export default function Attendance() {
   const isManagerOnlyView = role === "manager";
 
   const loadAttendance = () => {
+    const start = filterStartDate <= filterEndDate ? filterStartDate : filterEndDate;
+    const end = filterStartDate <= filterEndDate ? filterEndDate : filterStartDate;
     api
-      .get<Attendance[]>("/attendance")
-      .then((response) => setAttendance(response.data))
+      .get<Page<Attendance>>("/attendance", {
+        params: {
+          page: attendancePage,
+          limit: pageLimit,
+          from: start,
+          to: end,
+          employeeId: filterEmployeeId || undefined
+        }
+      })
+      .then((response) => {
+        if (response.data.items.length === 0 && attendancePage > 1) {
+          setAttendancePage(attendancePage - 1);
+          return;
+        }
+        setAttendance(response.data.items);
+        setAttendanceTotal(response.data.total);
+      })
+      .catch(() => setError("Could not load attendance"));
+
+    fetchAllPages<Attendance>("/attendance", { open: "true" })
+      .then(setOpenAttendance)
+      .catch(() => setError("Could not load attendance"));
+
+    fetchAllPages<Attendance>("/attendance", { from: todayDate, to: todayDate })
+      .then(setTodayAttendance)
       .catch(() => setError("Could not load attendance"));
   };
 
   const loadEmployees = () => {
-    api
-      .get<Employee[]>("/employees")
-      .then((response) => setEmployees(response.data))
+    fetchAllPages<Employee>("/employees")
+      .then(setEmployees)
       .catch(() => setError("Could not load employees"));
   };
 
   useEffect(() => {
     loadAttendance();
+  }, [attendancePage, filterEmployeeId, filterStartDate, filterEndDate]);
+
+  useEffect(() => {
+    setAttendancePage(1);
+  }, [filterEmployeeId, filterStartDate, filterEndDate]);
+
+  useEffect(() => {
     loadEmployees();
     me()
       .then((user) => {
@@ -106,8 +142,6 @@ export default function Attendance() {
 
   const employeeById = useMemo(() => new Map(employees.map((employee) => [employee.id, employee])), [employees]);
 
-  const openAttendance = useMemo(() => attendance.filter((record) => !record.checkOut), [attendance]);
-
   const selfEmployeeId = useMemo(() => currentUser?.employeeId ?? "", [currentUser]);
 
   const selfOpenAttendance = useMemo(
@@ -645,7 +679,7 @@ export default function Attendance() {
     if (!selfEmployeeId) {
       return null;
     }
-    const todayRecords = attendance.filter(
+    const todayRecords = todayAttendance.filter(
       (record) => record.employeeId === selfEmployeeId && getLocalDateKey(record.checkIn) === todayDate
     );
     if (todayRecords.length === 0) {
@@ -654,13 +688,13 @@ export default function Attendance() {
     return todayRecords.reduce((latest, record) =>
       new Date(record.checkIn).getTime() > new Date(latest.checkIn).getTime() ? record : latest
     );
-  }, [attendance, selfEmployeeId, todayDate]);
+  }, [todayAttendance, selfEmployeeId, todayDate]);
   const hasSelfCheckInToday = useMemo(() => {
     if (!selfEmployeeId) {
       return false;
     }
-    return attendance.some((record) => record.employeeId === selfEmployeeId && getLocalDateKey(record.checkIn) === todayDate);
-  }, [attendance, selfEmployeeId, todayDate]);
+    return todayAttendance.some((record) => record.employeeId === selfEmployeeId && getLocalDateKey(record.checkIn) === todayDate);
+  }, [todayAttendance, selfEmployeeId, todayDate]);
 
   return (
     <section className="panel">
@@ -1032,6 +1066,7 @@ export default function Attendance() {
           );
         })}
       </div>
+      <Pager page={attendancePage} limit={pageLimit} total={attendanceTotal} onChange={setAttendancePage} />
     </section>
   );
 }
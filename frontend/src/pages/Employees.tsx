@@ -4,9 +4,10 @@ import axios from "axios";
 import { z } from "zod";
 import { useForm } from "react-hook-form";
 import { zodResolver } from "@hookform/resolvers/zod";
-import api from "../api/client";
-import type { Employee, User } from "../api/types";
+import api, { pageLimit } from "../api/client";
+import type { Employee, Page, User } from "../api/types";
 import { me } from "../api/auth";
+import Pager from "../components/Pager";
 
 const schema = z
   .object({
@@ -25,6 +26,8 @@ type FormValues = z.infer<typeof schema>;
 
 export default function Employees() {
   const [employees, setEmployees] = useState<Employee[]>([]);
+  const [page, setPage] = useState(1);
+  const [total, setTotal] = useState(0);
   const [role, setRole] = useState<User["role"] | null>(null);
   const [serverError, setServerError] = useState<string | null>(null);
   const [isModalOpen, setIsModalOpen] = useState(false);
@@ -45,13 +48,23 @@ export default function Employees() {
 
   const load = () => {
     api
-      .get<Employee[]>("/employees")
-      .then((response) => setEmployees(response.data))
+      .get<Page<Employee>>("/employees", { params: { page, limit: pageLimit } })
+      .then((response) => {
+        if (response.data.items.length === 0 && page > 1) {
+          setPage(page - 1);
+          return;
+        }
+        setEmployees(response.data.items);
+        setTotal(response.data.total);
+      })
         .catch(() => setServerError("Could not load employees"));
   };
 
   useEffect(() => {
     load();
+  }, [page]);
+
+  useEffect(() => {
     me()
       .then((user) => {
         setRole(user.role);
@@ -277,6 +290,7 @@ export default function Employees() {
       ) : (
         renderEmployeeSection("Employees", regularEmployees)
       )}
+      <Pager page={page} limit={pageLimit} total={total} onChange={setPage} />
 
       {isModalOpen && (
         <div
@@ -1,16 +1,27 @@
 import { useEffect, useState } from "react";
 import { useNavigate } from "react-router-dom";
 import { z } from "zod";
-import { useForm } from "react-hook-form";
+import { useFieldArray, useForm } from "react-hook-form";
 import { zodResolver } from "@hookform/resolvers/zod";
-import api from "../api/client";
-import type { Invoice, User } from "../api/types";
+import api, { pageLimit } from "../api/client";
+import type { Customer, Invoice, Page, User } from "../api/types";
 import { me } from "../api/auth";
+import Pager from "../components/Pager";
 
 const schema = z.object({
-  number: z.string().min(1),
-  customerName: z.string().min(1),
-  amount: z.coerce.number().min(0),
+  number: z.string().trim().optional(),
+  customerId: z.string().min(1),
+  lines: z
+    .array(
+      z.object({
+        description: z.string().min(1),
+        quantity: z.coerce.number().positive(),
+        unitPrice: z.coerce.number().min(0),
+        discount: z.coerce.number().min(0).max(100),
+        taxRate: z.coerce.number().min(0).max(100)
+      })
+    )
+    .min(1),
   status: z.string().min(1),
   issuedAt: z.string().min(1),
   dueAt: z.string().min(1)
@@ -20,6 +31,9 @@ type FormValues = z.infer<typeof schema>;
 
 export default function Invoices() {
   const [invoices, setInvoices] = useState<Invoice[]>([]);
+  const [page, setPage] = useState(1);
+  const [total, setTotal] = useState(0);
+  const [customers, setCustomers] = useState<Customer[]>([]);
   const [role, setRole] = useState<User["role"] | null>(null);
   const [error, setError] = useState<string | null>(null);
   const [isModalOpen, setIsModalOpen] = useState(false);
@@ -27,21 +41,39 @@ export default function Invoices() {
   const navigate = useNavigate();
 
   const {
+    control,
     register,
     handleSubmit,
     reset,
     formState: { errors, isSubmitting }
   } = useForm<FormValues>({ resolver: zodResolver(schema) });
+  const { fields, append, remove } = useFieldArray({ control, name: "lines" });
+
+  const emptyLine = { description: "", quantity: 1, unitPrice: 0, discount: 0, taxRate: 0 };
 
   const load = () => {
     api
-      .get<Invoice[]>("/invoices")
-      .then((response) => setInvoices(response.data))
+      .get<Page<Invoice>>("/invoices", { params: { page, limit: pageLimit } })
+      .then((response) => {
+        if (response.data.items.length === 0 && page > 1) {
+          setPage(page - 1);
+          return;
+        }
+        setInvoices(response.data.items);
+        setTotal(response.data.total);
+      })
       .catch(() => setError("Could not load invoices"));
   };
 
   useEffect(() => {
     load();
+  }, [page]);
+
+  useEffect(() => {
+    api
+      .get<Customer[]>("/customers")
+      .then((response) => setCustomers(response.data))
+      .catch(() => setError("Could not load customers"));
     me()
       .then((user) => {
         setRole(user.role);
@@ -54,11 +86,14 @@ export default function Invoices() {
 
   const onSubmit = async (values: FormValues) => {
     setError(null);
+    // A blank number is left out so the server allocates the next one.
+    const { number, ...rest } = values;
+    const payload = number ? values : rest;
     try {
       if (editingInvoice) {
-        await api.put(`/invoices/${editingInvoice.id}`, values);
+        await api.put(`/invoices/${editingInvoice.id}`, payload);
       } else {
-        await api.post("/invoices", values);
+        await api.post("/invoices", payload);
       }
       reset();
       load();
@@ -73,8 +108,8 @@ export default function Invoices() {
     setEditingInvoice(null);
     reset({
       number: "",
-      customerName: "",
-      amount: 0,
+      customerId: "",
+      lines: [emptyLine],
       status: "draft",
       issuedAt: "",
       dueAt: ""
@@ -94,8 +129,16 @@ export default function Invoices() {
     setEditingInvoice(invoice);
     reset({
       number: invoice.number,
-      customerName: invoice.customerName,
-      amount: invoice.amount,
+      customerId: invoice.customerId ?? "",
+      lines: invoice.lines?.length
+        ? invoice.lines.map((line) => ({
+            description: line.description,
+            quantity: line.quantity,
+            unitPrice: line.unitPrice,
+            discount: line.discount ?? 0,
+            taxRate: line.taxRate
+          }))
+        : [emptyLine],
       status: invoice.status,
       issuedAt: toDateInput(invoice.issuedAt),
       dueAt: toDateInput(invoice.dueAt)
@@ -181,6 +224,7 @@ export default function Invoices() {
           ))
         )}
       </div>
+      <Pager page={page} limit={pageLimit} total={total} onChange={setPage} />
 
       {isModalOpen && (
         <div className="modal-backdrop" onClick={() => setIsModalOpen(false)}>
@@ -193,22 +237,58 @@ export default function Invoices() {
             </div>
             <form onSubmit={handleSubmit(onSubmit)}>
               <label>Invoice Number</label>
-              <input {...register("number")} />
-              {errors.number && <span className="error">Required</span>}
+              <input placeholder="Assigned automatically" {...register("number")} />
+              <div className="helper">Leave blank to use the next number in the sequence.</div>
+              {errors.number && <span className="error">Invalid invoice number</span>}
 
-              <label>Customer Name</label>
-              <input {...register("customerName")} />
-              {errors.customerName && <span className="error">Required</span>}
+              <label>Customer</label>
+              <select {...register("customerId")}>
+                <option value="">Select a customer</option>
+                {customers.map((customer) => (
+                  <option key={customer.id} value={customer.id}>
+                    {customer.name}
+                  </option>
+                ))}
+              </select>
+              {errors.customerId && <span className="error">Required</span>}
 
-              <label>Amount</label>
-              <input type="number" step="0.01" {...register("amount")} />
-              {errors.amount && <span className="error">Required</span>}
+              <label>Lines</label>
+              {fields.map((field, index) => (
+                <div className="invoice-line-row" key={field.id}>
+                  <input placeholder="Description" {...register(`lines.${index}.description`)} />
+                  <input type="number" step="0.01" placeholder="Qty" {...register(`lines.${index}.quantity`)} />
+                  <input
+                    type="number"
+                    step="0.01"
+                    placeholder="Unit price"
+                    {...register(`lines.${index}.unitPrice`)}
+                  />
+                  <input
+                    type="number"
+                    step="0.01"
+                    placeholder="Discount %"
+                    {...register(`lines.${index}.discount`)}
+                  />
+                  <input type="number" step="0.01" placeholder="Tax %" {...register(`lines.${index}.taxRate`)} />
+                  <button
+                    className="ghost"
+                    type="button"
+                    onClick={() => remove(index)}
+                    disabled={fields.length === 1}
+                  >
+                    Remove
+                  </button>
+                </div>
+              ))}
+              {errors.lines && <span className="error">Each line needs a description and quantity; discounts are 0-100%</span>}
+              <button className="ghost" type="button" onClick={() => append(emptyLine)}>
+                Add line
+              </button>
 
               <label>Status</label>
               <select {...register("status")}>
                 <option value="draft">draft</option>
-                <option value="paid">paid</option>
-                <option value="overdue">overdue</option>
+                <option value="sent">sent</option>
               </select>
               {errors.status && <span className="error">Required</span>}
 
@@ -4,13 +4,13 @@ import axios from "axios";
 import { z } from "zod";
 import { useForm } from "react-hook-form";
 import { zodResolver } from "@hookform/resolvers/zod";
-import api, { requestWithFallback } from "../api/client";
+import api, { fetchAllPages, requestWithFallback } from "../api/client";
 import type { Employee, LeaveBalance, LeavePolicy, LeaveRequest, User } from "../api/types";
 import { me } from "../api/auth";
 
 const schema = z.object({
   employeeId: z.string().min(1),
-  type: z.enum(["sick", "casual"]),
+  type: z.enum(["sick", "casual", "unpaid"]),
   startDate: z.string().min(1),
   endDate: z.string().min(1),
   reason: z.string().optional()
@@ -79,9 +79,8 @@ export default function Leaves() {
       .then((response) => setBalances(response.data))
       .catch(() => setError("Could not load balances"));
 
-    api
-      .get<Employee[]>("/employees")
-      .then((response) => setEmployees(response.data))
+    fetchAllPages<Employee>("/employees")
+      .then(setEmployees)
       .catch(() => setError("Could not load employees"));
   };
 
@@ -570,17 +569,17 @@ export default function Leaves() {
                               )}
                             </span>
                             <span>
-                              {isManager && request.status !== "approved" && (
+                              {isManager && request.status !== "approved" && request.status !== "cancelled" && (
                                 <button className="ghost" type="button" onClick={(event) => { event.stopPropagation(); handleApprove(request); }}>
                                   Approve
                                 </button>
                               )}
-                              {isManager && request.status !== "rejected" && (
+                              {isManager && request.status !== "rejected" && request.status !== "cancelled" && (
                                 <button className="ghost" type="button" onClick={(event) => { event.stopPropagation(); handleReject(request); }}>
                                   Reject
                                 </button>
                               )}
-                              {isManager && request.status !== "pending" && (
+                              {isManager && request.status !== "pending" && request.status !== "cancelled" && (
                                 <button className="ghost" type="button" onClick={(event) => { event.stopPropagation(); handlePending(request); }}>
                                   Pending
                                 </button>
@@ -656,6 +655,7 @@ export default function Leaves() {
               <select {...register("type")}>
                 <option value="sick">sick</option>
                 <option value="casual">casual</option>
+                <option value="unpaid">unpaid</option>
               </select>
               {errors.type && <span className="error">Required</span>}
 